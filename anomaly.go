@@ -0,0 +1,56 @@
+package useragent
+
+import "strings"
+
+// Anomalies reported in UserAgent.Anomalies
+const (
+	AnomalyImpossibleCombo = "impossible-combo" // browser and OS which don't exist together, like Safari 6+ on Windows
+	AnomalyTruncated       = "truncated"        // unbalanced parentheses
+	AnomalyRepeatedMozilla = "repeated-mozilla" // more than one Mozilla/ prefix
+	AnomalyFakeBot         = "fake-bot"         // known bot name with wrong platform or without bot URL
+	AnomalyControlChars    = "control-chars"    // control characters, like CR and LF used for header injection
+)
+
+// findAnomalies returns structural red flags of the parsed user agent,
+// or nil if there are none
+func findAnomalies(ua *UserAgent) []string {
+	var anomalies []string
+
+	switch {
+	case ua.Name == Safari && ua.OS == Windows && ua.VersionNo.Major >= 6, // last Safari for Windows is 5.1.7
+		ua.Name == Safari && ua.OS == Android,
+		ua.Name == InternetExplorer && (ua.OS == Android || ua.OS == IOS || ua.OS == Linux):
+		anomalies = append(anomalies, AnomalyImpossibleCombo)
+	}
+
+	open, controls := 0, false
+	for i := 0; i < len(ua.Raw); i++ {
+		switch c := ua.Raw[i]; {
+		case c == '(':
+			open++
+		case c == ')':
+			open--
+		case c < 0x20 && c != '\t', c == 0x7f:
+			controls = true
+		}
+	}
+	if open > 0 {
+		anomalies = append(anomalies, AnomalyTruncated)
+	}
+	if strings.Count(ua.Raw, "Mozilla/") > 1 {
+		anomalies = append(anomalies, AnomalyRepeatedMozilla)
+	}
+
+	// Googlebot and Bingbot always send URL and never run on desktop OS or iOS
+	switch ua.Name {
+	case Googlebot, Bingbot:
+		if ua.URL == "" || ua.OS == Windows || ua.OS == MacOS || ua.OS == IOS {
+			anomalies = append(anomalies, AnomalyFakeBot)
+		}
+	}
+
+	if controls {
+		anomalies = append(anomalies, AnomalyControlChars)
+	}
+	return anomalies
+}
@@ -0,0 +1,31 @@
+package useragent_test
+
+import (
+	"reflect"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestAnomalies(t *testing.T) {
+	tests := []struct {
+		ua        string
+		anomalies []string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", nil},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", nil},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.1 Safari/605.1.15", []string{ua.AnomalyImpossibleCombo}},
+		{"Mozilla/5.0 (Windows; U; Windows NT 6.1; en-US) AppleWebKit/534.57.2 (KHTML, like Gecko) Version/5.1.7 Safari/534.57.2", nil},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko", []string{ua.AnomalyTruncated}},
+		{"Mozilla/5.0 Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", []string{ua.AnomalyRepeatedMozilla}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; compatible; Googlebot/2.1; +http://www.google.com/bot.html)", []string{ua.AnomalyFakeBot}},
+		{"Googlebot/2.1", []string{ua.AnomalyFakeBot}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36\r\nX-Injected: 1", []string{ua.AnomalyControlChars}},
+		{"Mozilla/5.0 Mozilla/5.0 (Windows NT 10.0; Win64; x64) Version/16.1 Safari/605.1.15 (\n", []string{ua.AnomalyImpossibleCombo, ua.AnomalyTruncated, ua.AnomalyRepeatedMozilla, ua.AnomalyControlChars}},
+	}
+	for _, test := range tests {
+		if anomalies := ua.Parse(test.ua).Anomalies; !reflect.DeepEqual(anomalies, test.anomalies) {
+			t.Errorf("\n%q\nAnomalies should be %v not %v", test.ua, test.anomalies, anomalies)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package useragent
+
+// Anonymize returns generalized copy of the user agent for privacy friendly
+// logging and analytics, similar to user agent reduction in Chrome. Versions
+// are reduced to the major version, Windows keeps the NT version since it
+// only identifies the release. Device name, vendor and model are removed,
+// only the device type remains. Raw user agent, URLs, contact email, locale
+// and region, screen, Android build, tokens and debug info are removed, since
+// they can identify the visitor. Name of unrecognized user agent, which is the
+// whole raw user agent, is removed as well.
+func (ua UserAgent) Anonymize() UserAgent {
+	ua.Version = majorVersion(ua.Version)
+	ua.VersionNo = VersionNo{Major: ua.VersionNo.Major}
+	if ua.OS != Windows {
+		ua.OSVersion = majorVersion(ua.OSVersion)
+		ua.OSVersionNo = VersionNo{Major: ua.OSVersionNo.Major}
+	}
+	ua.OSDistroVersion = majorVersion(ua.OSDistroVersion)
+	ua.AppVersion = majorVersion(ua.AppVersion)
+	ua.BrowserVersion = majorVersion(ua.BrowserVersion)
+	ua.EngineVersion = majorVersion(ua.EngineVersion)
+	ua.Electron = majorVersion(ua.Electron)
+	ua.HMSCore = majorVersion(ua.HMSCore)
+
+	if ua.Name == ua.Raw {
+		// not recognized, name may contain emails, session ids or URLs
+		ua.Name = ""
+	}
+	ua.Device, ua.DeviceVendor, ua.DeviceModel = "", "", ""
+	ua.Raw, ua.String, ua.URL, ua.BotContact = "", "", "", ""
+	ua.URLs = nil
+	ua.Locale, ua.Region, ua.AndroidBuild = "", "", ""
+	ua.Screen = Screen{}
+	ua.Debug = nil
+	ua.Tokens = nil
+	return ua
+}
+
+// majorVersion returns leading digits of the version, like 120 for 120.0.6099.283
+func majorVersion(version string) string {
+	i := 0
+	for i < len(version) && '0' <= version[i] && version[i] <= '9' {
+		i++
+	}
+	return version[:i]
+}
@@ -0,0 +1,48 @@
+package useragent
+
+// Headless browsers and browser automation framework names, reported in AutomationTool
+const (
+	PhantomJS  = "PhantomJS"
+	Selenium   = "Selenium"
+	Playwright = "Playwright"
+	Puppeteer  = "Puppeteer"
+	Splash     = "Splash"
+	Rod        = "Rod"
+	Chromedp   = "chromedp"
+)
+
+// automationClients are tokens of headless browsers and automation framework
+// clients reported as browser name
+var automationClients = []string{"PhantomJS", "Selenium", "WebDriver", "Playwright", "Puppeteer", "splash Version", "splash"}
+
+var automationNames = map[string]string{
+	"PhantomJS":      PhantomJS,
+	"Selenium":       Selenium,
+	"WebDriver":      Selenium,
+	"Playwright":     Playwright,
+	"Puppeteer":      Puppeteer,
+	"splash Version": Splash,
+	"splash":         Splash,
+	"rod":            Rod,
+	"chromedp":       Chromedp,
+}
+
+// findAutomationTool returns name of the headless browser or automation
+// framework which sent the request. Frameworks driving headless Chrome with
+// its default user agent are reported as Headless Chrome, or as Electron
+// for Electron based ones, like Nightmare.
+func (p properties) findAutomationTool() string {
+	if key, _ := p.getAny(automationClients...); key != "" {
+		return automationNames[key]
+	}
+	if key, _ := p.getAny("rod", "chromedp"); key != "" {
+		return automationNames[key]
+	}
+	if p.exists("HeadlessChrome") {
+		if p.exists("Electron") {
+			return "Electron"
+		}
+		return HeadlessChrome
+	}
+	return ""
+}
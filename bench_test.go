@@ -0,0 +1,96 @@
+package useragent_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	ua "github.com/mileusna/useragent"
+)
+
+// benchmark user agents per category
+var benchUA = []struct {
+	name   string
+	ua     string
+	allocs float64 // allocation budget per Parse call
+}{
+	{"DesktopChrome", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", 3},
+	{"AndroidDevice", "Mozilla/5.0 (Linux; Android 6.0.1; SAMSUNG SM-A310F/A310FXXU2BQB1 Build/MMB29K) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/5.4 Chrome/51.0.2704.106 Mobile Safari/537.36", 12},
+	{"Bot", "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", 14},
+	{"Garbage", "\x00\xff;;((//))[[]]:: http:// %s%s%n ${jndi:ldap://x} ;;; /// ((( )))", 18},
+	{"LongUA", "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 " + strings.Repeat("Extension/1.0 ", 150), 310},
+}
+
+func benchmarkParse(b *testing.B, s string) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testUA = ua.Parse(s)
+	}
+}
+
+func BenchmarkDesktopChrome(b *testing.B) { benchmarkParse(b, benchUA[0].ua) }
+func BenchmarkAndroidDevice(b *testing.B) { benchmarkParse(b, benchUA[1].ua) }
+func BenchmarkBot(b *testing.B)           { benchmarkParse(b, benchUA[2].ua) }
+func BenchmarkGarbage(b *testing.B)       { benchmarkParse(b, benchUA[3].ua) }
+func BenchmarkLongUA(b *testing.B)        { benchmarkParse(b, benchUA[4].ua) }
+
+// TestAllocations fails if number of allocations per Parse call
+// exceeds the budget for any of the benchmark categories
+func TestAllocations(t *testing.T) {
+	for _, bench := range benchUA {
+		allocs := testing.AllocsPerRun(100, func() {
+			testUA = ua.Parse(bench.ua)
+		})
+		t.Log(bench.name, allocs)
+		if allocs > bench.allocs {
+			t.Errorf("%s: %.0f allocations per Parse, budget is %.0f", bench.name, allocs, bench.allocs)
+		}
+	}
+}
+
+// BenchmarkRetainUserAgent and BenchmarkRetainLite keep all parse results
+// in memory, like pipelines holding millions of parsed user agents, B/op
+// includes the retained result and size-B reports the struct size
+func BenchmarkRetainUserAgent(b *testing.B) {
+	b.ReportAllocs()
+	results := make([]ua.UserAgent, b.N)
+	for i := range results {
+		results[i] = ua.Parse(benchUA[1].ua)
+	}
+	b.ReportMetric(float64(unsafe.Sizeof(results[0])), "size-B")
+}
+
+func BenchmarkRetainLite(b *testing.B) {
+	b.ReportAllocs()
+	results := make([]ua.Lite, b.N)
+	for i := range results {
+		results[i] = ua.ParseLite(benchUA[1].ua)
+	}
+	b.ReportMetric(float64(unsafe.Sizeof(results[0])), "size-B")
+}
+
+var testBool bool
+
+// BenchmarkIsBotBrowser should stay more than 10x faster than Parse of the
+// same user agent in BenchmarkAndroidDevice (550 ns and 7 µs, 13x, when the
+// bot hints were merged with the client categories)
+func BenchmarkIsBotBrowser(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testBool = ua.IsBot(benchUA[1].ua)
+	}
+}
+
+func BenchmarkIsBotBot(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testBool = ua.IsBot(benchUA[2].ua)
+	}
+}
+
+func BenchmarkIsMobile(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testBool = ua.IsMobile(benchUA[0].ua)
+	}
+}
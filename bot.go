@@ -0,0 +1,222 @@
+package useragent
+
+import "strings"
+
+// Reasons for setting Bot flag, reported in BotReason
+const (
+	BotReasonKnown   = "known"   // user agent matched known bot rule
+	BotReasonKeyword = "keyword" // name contains bot keyword
+	BotReasonURL     = "url"     // URL with bot keyword or known bot domain
+)
+
+// Googlebot crawlers, returned by GooglebotType
+const (
+	GooglebotDesktop    = "desktop"
+	GooglebotSmartphone = "smartphone"
+)
+
+// GooglebotType returns GooglebotSmartphone for Googlebot crawling as Android
+// phone, GooglebotDesktop for the desktop crawler, or empty string if user
+// agent is not Googlebot. Googlebot-Image and other special purpose crawlers
+// have their own names.
+func (ua UserAgent) GooglebotType() string {
+	switch {
+	case ua.Name != Googlebot:
+		return ""
+	case ua.Mobile:
+		return GooglebotSmartphone
+	}
+	return GooglebotDesktop
+}
+
+// botKeywords found in bot names and URLs
+var botKeywords = []string{"bot", "spider", "crawl", "fetch", "monitor", "preview"}
+
+// botDomains are domains of known bot operators
+var botDomains = []string{
+	"google.com", "bing.com", "msn.com", "yandex.com", "yandex.ru", "apple.com",
+	"baidu.com", "yahoo.com", "duckduckgo.com", "facebook.com", "twitter.com",
+	"semrush.com", "ahrefs.com", "mj12bot.com", "bytedance.com", "petalsearch.com",
+}
+
+// hasBotKeyword returns true if s contains any of the bot keywords, case insensitive
+func hasBotKeyword(s string) bool {
+	for _, k := range botKeywords {
+		if containsFold(s, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBotDomain returns true if URL host is one of the known bot domains or its subdomain
+func isBotDomain(url string) bool {
+	host := url
+	if i := strings.Index(host, "://"); i != -1 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i != -1 {
+		host = host[:i]
+	}
+	for _, d := range botDomains {
+		if len(host) < len(d) || !strings.EqualFold(host[len(host)-len(d):], d) {
+			continue
+		}
+		if len(host) == len(d) || host[len(host)-len(d)-1] == '.' {
+			return true
+		}
+	}
+	return false
+}
+
+// isBotURL returns true if URL found in user agent is sent by a bot. URL
+// alone is not enough since some apps and browser shells embed their home
+// page into user agent, so URL must contain bot keyword (or name or contact
+// email must) or be on known bot domain.
+func isBotURL(url, name, contact string) bool {
+	if url == "" {
+		return false
+	}
+	return hasBotKeyword(url) || hasBotKeyword(name) || hasBotKeyword(contact) || isBotDomain(url)
+}
+
+// isInfoURL returns true if URL is sent with + prefix, the convention of
+// crawlers linking their info page, like "BUbiNG (+http://law.di.unimi.it/BUbiNG.html)"
+func isInfoURL(userAgent, url string) bool {
+	return url != "" && strings.Contains(userAgent, "+"+url)
+}
+
+// findContact returns contact email sent by bots and scripts, like
+// "spider-feedback@bytedance.com", "mailto:ops@example.com" or
+// "contact: bot@example.com", with mailto or other label removed
+func (p properties) findContact() string {
+	for _, prop := range p.list {
+		if strings.IndexByte(prop.Key, '@') == -1 {
+			continue
+		}
+		s := prop.Key
+		if i := strings.LastIndexByte(s, ' '); i != -1 {
+			s = s[i+1:]
+		}
+		if isEmail(s) {
+			return s
+		}
+	}
+	return ""
+}
+
+// isEmail returns true if s looks like email address, local part and
+// domain with a top level domain of letters, like bot@example.com, so
+// versions like "ios@9.1.0" are not matched
+func isEmail(s string) bool {
+	at := strings.IndexByte(s, '@')
+	if at < 1 || strings.IndexByte(s[at+1:], '@') != -1 {
+		return false
+	}
+	dot := strings.LastIndexByte(s, '.')
+	if dot <= at+1 || len(s)-dot-1 < 2 {
+		return false
+	}
+	for i := dot + 1; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBot returns true if user agent is a bot, same as Parse(userAgent).Bot.
+// User agents without any of the bot hints, like most of the browsers,
+// are rejected by a quick scan without parsing. The scan knows only the
+// builtin detection, so user agents are always parsed when the parser set
+// by SetDefault is used, since its rules, matchers or filters may detect
+// bots without the hints.
+func IsBot(userAgent string) bool {
+	p := defaultParser()
+	if p == builtinParser && !hasBotHint(userAgent) {
+		return false
+	}
+	return p.Parse(userAgent).Bot
+}
+
+// botHints are substrings found in every user agent detected as bot by
+// Parse, except bot clients of the client categories, like scanners
+var botHints = [256][]string{
+	'b': {"bot"},
+	's': {"spider", "selenium", "splash"},
+	'h': {"http", "headless"},
+	'p': {"prober", "producer", "preview", "phantomjs", "playwright", "puppeteer"},
+	'm': {"mediapartners"},
+	'y': {"yahoo", "yandex"},
+	'f': {"facebook"},
+	'w': {"webdriver"},
+	'r': {"rod"},
+	'c': {"chromedp", "com.apple.webkit.networking"},
+}
+
+// botClients are clients of the bot categories, like scanners and webhooks,
+// grouped by the first letter of the hint
+var botClients = func() (clients [256][]rawClient) {
+	for _, cat := range clientCategories {
+		if !cat.bot {
+			continue
+		}
+		for c, group := range cat.clients {
+			clients[c] = append(clients[c], group...)
+		}
+	}
+	return clients
+}()
+
+// botHintStart marks first letters of bot hints and bot clients
+var botHintStart = func() (start [256]bool) {
+	for c := range start {
+		start[c] = len(botHints[c]) > 0 || len(botClients[c]) > 0
+	}
+	return start
+}()
+
+// hasBotHint returns true if s contains any of the bot hints, case
+// insensitive, or a bot client of the same client categories Parse uses,
+// so the user agent is scanned once
+func hasBotHint(s string) bool {
+	// all hints are longer than one letter, second letter is compared first
+	for i := 0; i < len(s)-1; i++ {
+		c := s[i] | 0x20 // lowercase letters, other bytes don't match hints
+		if !botHintStart[c] {
+			continue
+		}
+		next := s[i+1] | 0x20
+		for _, hint := range botHints[c] {
+			if hint[1] == next && hasPrefixFold(s[i:], hint) {
+				return true
+			}
+		}
+		if !isWordStart(s, i) {
+			continue
+		}
+		for _, client := range botClients[c] {
+			if client.hint[1] == next && hasPrefixFold(s[i:], client.hint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasPrefixFold returns true if s starts with lowercase prefix, ASCII case insensitive
+func hasPrefixFold(s, prefix string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package useragent
+
+import "strings"
+
+// Brand is an entry of Sec-CH-UA or Sec-CH-UA-Full-Version-List client
+// hints, like "Google Chrome";v="120.0.6099.130"
+type Brand struct {
+	Name    string
+	Version string
+}
+
+// Grease returns true for GREASE brands, like "Not.A/Brand" or "Not_A Brand",
+// which browsers send with random punctuation to prevent brand sniffing
+func (b Brand) Grease() bool {
+	var letters int
+	const want = "notabrand"
+	for i := 0; i < len(b.Name); i++ {
+		c := b.Name[i] | 0x20
+		if c < 'a' || c > 'z' {
+			continue
+		}
+		if letters == len(want) || c != want[letters] {
+			return false
+		}
+		letters++
+	}
+	return letters == len(want)
+}
+
+// brandNames are client hints brands which differ from browser names
+var brandNames = map[string]string{
+	"Google Chrome":    Chrome,
+	"Microsoft Edge":   Edge,
+	"Opera GX":         OperaGX,
+	"Samsung Internet": SamsungBrowser,
+	"Yandex":           "YaBrowser",
+}
+
+// ParseBrands parses brand list of Sec-CH-UA or Sec-CH-UA-Full-Version-List
+// header, a structured header list of strings with v parameter. Brands are
+// returned in the header order, GREASE brands included. Parsing stops at
+// the first malformed entry.
+func ParseBrands(header string) []Brand {
+	var brands []Brand
+	s := brandScanner{s: header}
+	for {
+		s.skipSpace()
+		if s.done() {
+			return brands
+		}
+		name, ok := s.quoted()
+		if !ok {
+			return brands
+		}
+		b := Brand{Name: name}
+		// parameters, only v is used
+		for s.skipSpace(); s.peek() == ';'; s.skipSpace() {
+			s.i++
+			s.skipSpace()
+			key := s.token()
+			var value string
+			if s.peek() == '=' {
+				s.i++
+				if s.peek() == '"' {
+					if value, ok = s.quoted(); !ok {
+						return brands
+					}
+				} else {
+					value = s.token()
+				}
+			}
+			if key == "v" {
+				b.Version = value
+			}
+		}
+		brands = append(brands, b)
+		if s.done() {
+			return brands
+		}
+		if s.peek() != ',' {
+			return brands
+		}
+		s.i++
+	}
+}
+
+// brandScanner is tokenizer of structured header brand lists
+type brandScanner struct {
+	s string
+	i int
+}
+
+func (s *brandScanner) done() bool {
+	return s.i >= len(s.s)
+}
+
+func (s *brandScanner) peek() byte {
+	if s.done() {
+		return 0
+	}
+	return s.s[s.i]
+}
+
+func (s *brandScanner) skipSpace() {
+	for !s.done() && (s.s[s.i] == ' ' || s.s[s.i] == '\t') {
+		s.i++
+	}
+}
+
+// quoted returns unescaped quoted string, GREASE brands contain separators
+// like ";" or "," which are not special inside the quotes
+func (s *brandScanner) quoted() (string, bool) {
+	if s.peek() != '"' {
+		return "", false
+	}
+	s.i++
+	var sb strings.Builder
+	for !s.done() {
+		c := s.s[s.i]
+		s.i++
+		switch c {
+		case '\\':
+			if s.done() {
+				return "", false
+			}
+			sb.WriteByte(s.s[s.i])
+			s.i++
+		case '"':
+			return sb.String(), true
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+// token returns bare item, like parameter key or unquoted value
+func (s *brandScanner) token() string {
+	start := s.i
+	for !s.done() && !strings.ContainsRune(",;= \t", rune(s.s[s.i])) {
+		s.i++
+	}
+	return s.s[start:s.i]
+}
+
+// Browser returns the primary browser of the brand list, full version list
+// is used if set. GREASE brands and Chromium, sent by all Chromium based
+// browsers, are skipped since brand order is randomized. Chromium is returned
+// only if there is no other brand. Brand names are reported as browser names
+// of the package, like Chrome for "Google Chrome".
+func (h ClientHints) Browser() Brand {
+	header := h.FullVersionList
+	if header == "" {
+		header = h.Brands
+	}
+	var primary Brand
+	for _, b := range ParseBrands(header) {
+		if b.Grease() {
+			continue
+		}
+		if name, ok := brandNames[b.Name]; ok {
+			b.Name = name
+		}
+		if b.Name != "Chromium" {
+			return b
+		}
+		primary = b
+	}
+	return primary
+}
@@ -0,0 +1,147 @@
+package useragent
+
+import "strings"
+
+// Client categories, reported in Category
+const (
+	CategoryScanner         = "scanner"          // vulnerability scanner or internet-wide survey
+	CategoryFeedReader      = "feed reader"      // RSS and Atom reader or podcast client
+	CategoryAICrawler       = "ai crawler"       // crawler collecting AI training data, or robots.txt token to opt out of it
+	CategoryDownloadManager = "download manager" // download manager or video downloader, fetching files in bulk
+	CategoryMediaPlayer     = "media player"     // media player or playback library of streaming client
+	CategoryPackageManager  = "package manager"  // package manager or registry client
+	CategoryDevTool         = "dev tool"         // git client, CI/CD agent or repository webhook
+)
+
+// aiCrawlers are names of the clients reported with CategoryAICrawler
+var aiCrawlers = []string{ApplebotExtended}
+
+// AICrawlers returns names of AI crawlers reported with CategoryAICrawler,
+// which are also their robots.txt tokens, so they can be used to generate
+// rules opting out of AI training, like "User-agent: Applebot-Extended"
+func AICrawlers() []string {
+	return append([]string(nil), aiCrawlers...)
+}
+
+// rawClient is client name with the lowercase hint found in its user agent
+// and the token holding its version
+type rawClient struct {
+	hint  string
+	name  string
+	token string
+}
+
+// clientCategory is category of clients found in the raw user agent, with
+// the Bot and Tool flags reported for its clients
+type clientCategory struct {
+	category string
+	bot      bool
+	tool     bool
+	clients  *[256][]rawClient
+}
+
+// clientCategories in the order of precedence, client of the first category
+// found in the user agent is reported
+var clientCategories = [...]clientCategory{
+	// scanners often send browser user agent with their name appended
+	{CategoryScanner, true, false, &scanners},
+	// feed readers fetch on behalf of their subscribers, they are neither
+	// bots nor browsers
+	{CategoryFeedReader, false, false, &feedReaders},
+	// media players stream video and audio, usually from apps playing
+	// the media, they are neither bots nor browsers
+	{CategoryMediaPlayer, false, false, &mediaPlayers},
+	// download managers fetch files in bulk, they are tools, not bots
+	{CategoryDownloadManager, false, true, &downloadManagers},
+	// package managers and registry clients, like pip or docker, often
+	// append the HTTP library they use, like curl
+	{CategoryPackageManager, false, true, &packageManagers},
+	// repository webhooks are bots, git clients and CI/CD agents are tools
+	{CategoryDevTool, true, false, &webhooks},
+	{CategoryDevTool, false, true, &devTools},
+}
+
+// categoryClient is client with the index of its category in clientCategories
+type categoryClient struct {
+	rawClient
+	rank int
+}
+
+// categoryClients are clients of all categories, grouped by the first
+// letter of the hint, so the user agent is scanned once for all of them
+var categoryClients = func() (clients [256][]categoryClient) {
+	for rank, cat := range clientCategories {
+		for c, group := range cat.clients {
+			for _, client := range group {
+				clients[c] = append(clients[c], categoryClient{client, rank})
+			}
+		}
+	}
+	return clients
+}()
+
+// findClient returns client of the first category found in the user agent,
+// case insensitive. Raw user agent is searched for clients which send free
+// text or append their name to the browser user agent. Hints are matched at
+// the start of a word, so "git/" is not found in "Digit/1".
+func findClient(s string) (categoryClient, bool) {
+	found := categoryClient{rank: len(clientCategories)}
+	for i := 0; i < len(s); i++ {
+		if !isWordStart(s, i) {
+			continue
+		}
+		for _, c := range categoryClients[s[i]|0x20] {
+			if c.rank < found.rank && hasPrefixFold(s[i:], c.hint) {
+				if c.rank == 0 {
+					return c, true
+				}
+				found = c
+			}
+		}
+	}
+	return found, found.rank < len(clientCategories)
+}
+
+// isWordStart returns true if s[i] is the first character of the string or
+// follows a character which is not an ASCII letter or digit
+func isWordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	c := s[i-1] | 0x20
+	return !('a' <= c && c <= 'z' || '0' <= s[i-1] && s[i-1] <= '9')
+}
+
+// findClientVersion returns version of the client sent as value of its
+// token, like "VLC/3.0.20", as a word of the token, like "GStreamer
+// souphttpsrc 1.22.0 libsoup/3.4.4" or "cargo 1.75.0", appended to the
+// name, like "Lavf53.32.100", or as value of the token starting with the
+// name, like "NuGet Command Line/6.7.0" or "argocd-repo-server/v2.9.3"
+func (p properties) findClientVersion(token string) string {
+	if token == "" {
+		return ""
+	}
+	if v := p.get(token); v != "" {
+		return trimVersionPrefix(v)
+	}
+	for _, prop := range p.list {
+		if !strings.HasPrefix(prop.Key, token) {
+			continue
+		}
+		for _, w := range strings.Fields(prop.Key[len(token):]) {
+			if '0' <= w[0] && w[0] <= '9' {
+				return findVersion(w)
+			}
+		}
+		return trimVersionPrefix(prop.Value)
+	}
+	return ""
+}
+
+// trimVersionPrefix removes v prefix of the version, like "v2.9.3"
+func trimVersionPrefix(v string) string {
+	if len(v) > 1 && v[0] == 'v' && '0' <= v[1] && v[1] <= '9' {
+		return v[1:]
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package useragent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CorpusEntry is user agent string with expected parsing results, used for
+// regression testing. One entry is stored as JSON object on a single line:
+//
+//	{"ua": "Wget/1.12 (linux-gnu)", "name": "Wget", "version": "1.12", "os": ""}
+//
+// Type can be mobile, tablet, desktop, tv, console, headset or bot, and it is not checked if empty.
+// OS and Device are checked only if present, so "os": "" expects empty OS.
+type CorpusEntry struct {
+	UserAgent string  `json:"ua"`
+	Name      string  `json:"name"`
+	Version   string  `json:"version"`
+	Type      string  `json:"type,omitempty"`
+	OS        *string `json:"os,omitempty"`
+	Device    *string `json:"device,omitempty"`
+}
+
+// ReadCorpus reads corpus entries from r, one JSON object per line.
+// Empty lines and lines starting with # are skipped.
+func ReadCorpus(r io.Reader) ([]CorpusEntry, error) {
+	var entries []CorpusEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 || b[0] == '#' {
+			continue
+		}
+		var e CorpusEntry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return entries, fmt.Errorf("corpus line %d: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Check compares parsed user agent with expected results and returns
+// list of differences, or nil if all expected values match
+func (e CorpusEntry) Check(ua UserAgent) []string {
+	var diff []string
+	if ua.Name != e.Name {
+		diff = append(diff, fmt.Sprintf("Name should be %q not %q", e.Name, ua.Name))
+	}
+	if ua.Version != e.Version {
+		diff = append(diff, fmt.Sprintf("Version should be %q not %q", e.Version, ua.Version))
+	}
+	switch {
+	case e.Type == "desktop" && ua.Mobile:
+		diff = append(diff, "should be desktop type not mobile")
+	case e.Type == "mobile" && !ua.Mobile:
+		diff = append(diff, "should be mobile")
+	case e.Type == "tablet" && !ua.Tablet:
+		diff = append(diff, "should be tablet")
+	case e.Type == "tv" && ua.DeviceType() != DeviceTV:
+		diff = append(diff, "should be tv")
+	case e.Type == "console" && ua.DeviceType() != DeviceConsole:
+		diff = append(diff, "should be console")
+	case e.Type == "headset" && ua.DeviceType() != DeviceHeadset:
+		diff = append(diff, "should be headset")
+	case e.Type == "bot" && !ua.Bot:
+		diff = append(diff, "should be bot")
+	}
+	if e.OS != nil && *e.OS != ua.OS {
+		diff = append(diff, fmt.Sprintf("OS should be %q not %q", *e.OS, ua.OS))
+	}
+	if e.Device != nil && *e.Device != ua.Device {
+		diff = append(diff, fmt.Sprintf("Device should be %q not %q", *e.Device, ua.Device))
+	}
+	return diff
+}
@@ -0,0 +1,137 @@
+// Package corpus provides the test corpus of useragent package, user agents
+// with expected parsing results, so services using the package can run
+// conformance checks after upgrading it and gate deployments on no
+// regressions in the categories of their traffic:
+//
+//	for _, r := range corpus.Check(useragent.Parse) {
+//		if r.Category == corpus.Bots && r.Failed > 0 {
+//			log.Fatalf("%d of %d bots not detected", r.Failed, r.Total)
+//		}
+//	}
+//
+// The corpus grows with every release, expected results of the entries
+// may change when detection is improved.
+package corpus
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/mileusna/useragent"
+)
+
+// Categories of the corpus entries, the corpus file the entry is stored in
+const (
+	Apps    = "apps"    // in-app browsers, desktop apps and native app HTTP stacks
+	Bots    = "bots"    // crawlers, previews, automation and scanners
+	Desktop = "desktop" // desktop browsers
+	Mobile  = "mobile"  // mobile and tablet browsers
+	Other   = "other"   // TVs, consoles, e-readers, tools, feed readers and other clients
+)
+
+//go:embed data/*.jsonl
+var files embed.FS
+
+// Entry is user agent with expected parsing results, the category and
+// the section of the corpus file it is listed in, like "Google ads bots"
+type Entry struct {
+	useragent.CorpusEntry
+	Category string
+	Section  string
+}
+
+// Entries returns all corpus entries, ordered by category and by their
+// order in the corpus file
+func Entries() []Entry {
+	entries, err := readEntries(files)
+	if err != nil {
+		// corpus files are checked by the tests, they are embedded
+		// so the error is not expected
+		panic("corpus: " + err.Error())
+	}
+	return entries
+}
+
+// Categories returns categories of the corpus
+func Categories() []string {
+	return []string{Apps, Bots, Desktop, Mobile, Other}
+}
+
+// Failure is corpus entry not parsed as expected
+type Failure struct {
+	Entry Entry
+	Diff  []string // differences reported by CorpusEntry.Check
+}
+
+// Result of checking one category of the corpus
+type Result struct {
+	Category string
+	Total    int
+	Failed   int
+	Failures []Failure
+}
+
+// Check parses every corpus entry with parse, like useragent.Parse or
+// Parse method of the configured Parser, and returns results per category,
+// in the order of Categories
+func Check(parse func(string) useragent.UserAgent) []Result {
+	results := make([]Result, 0, len(Categories()))
+	index := map[string]int{}
+	for _, c := range Categories() {
+		index[c] = len(results)
+		results = append(results, Result{Category: c})
+	}
+	for _, e := range Entries() {
+		r := &results[index[e.Category]]
+		r.Total++
+		if diff := e.Check(parse(e.UserAgent)); diff != nil {
+			r.Failed++
+			r.Failures = append(r.Failures, Failure{Entry: e, Diff: diff})
+		}
+	}
+	return results
+}
+
+// readEntries reads corpus files of fsys, file name is the category
+func readEntries(fsys fs.FS) ([]Entry, error) {
+	names, err := fs.Glob(fsys, "data/*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		category := strings.TrimSuffix(strings.TrimPrefix(name, "data/"), ".jsonl")
+		section := ""
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			s := bytes.TrimSpace(scanner.Bytes())
+			switch {
+			case len(s) == 0:
+			case s[0] == '#':
+				section = strings.TrimSpace(string(s[1:]))
+			default:
+				e := Entry{Category: category, Section: section}
+				if err := json.Unmarshal(s, &e.CorpusEntry); err != nil {
+					return entries, fmt.Errorf("%s line %d: %v", name, line, err)
+				}
+				entries = append(entries, e)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,49 @@
+package corpus_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/useragent"
+	"github.com/mileusna/useragent/corpus"
+)
+
+func TestCheck(t *testing.T) {
+	total := 0
+	for _, r := range corpus.Check(useragent.Parse) {
+		if r.Total == 0 {
+			t.Errorf("category %s has no entries", r.Category)
+		}
+		if r.Failed != len(r.Failures) {
+			t.Errorf("category %s: %d failed, %d failures", r.Category, r.Failed, len(r.Failures))
+		}
+		for _, f := range r.Failures {
+			t.Error("\n", f.Entry.UserAgent, "\n", f.Entry.Category, f.Entry.Section, f.Diff)
+		}
+		total += r.Total
+	}
+	if n := len(corpus.Entries()); total != n {
+		t.Errorf("categories have %d entries, corpus %d", total, n)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	categories := map[string]bool{}
+	for _, c := range corpus.Categories() {
+		categories[c] = true
+	}
+	for _, e := range corpus.Entries() {
+		if !categories[e.Category] {
+			t.Error("unknown category", e.Category, e.UserAgent)
+		}
+		if e.Section == "" {
+			t.Error("entry without section", e.Category, e.UserAgent)
+		}
+	}
+
+	// failures are reported per category
+	for _, r := range corpus.Check(func(string) useragent.UserAgent { return useragent.UserAgent{} }) {
+		if r.Failed == 0 {
+			t.Errorf("category %s should fail with empty results", r.Category)
+		}
+	}
+}
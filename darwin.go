@@ -0,0 +1,63 @@
+package useragent
+
+import (
+	"net/url"
+	"strings"
+)
+
+// darwinVersions maps Darwin kernel major version to iOS and macOS version
+// released with it
+var darwinVersions = map[string][2]string{
+	"14": {"8", "10.10"},
+	"15": {"9", "10.11"},
+	"16": {"10", "10.12"},
+	"17": {"11", "10.13"},
+	"18": {"12", "10.14"},
+	"19": {"13", "10.15"},
+	"20": {"14", "11"},
+	"21": {"15", "12"},
+	"22": {"16", "13"},
+	"23": {"17", "14"},
+	"24": {"18", "15"},
+	"25": {"26", "26"},
+}
+
+// findDarwinOS returns OS and OS version for the Darwin kernel version sent
+// by CFNetwork. Only macOS sends the CPU architecture token, so requests
+// without it are reported as iOS. Version is unknown if Darwin is not in the table.
+func (p properties) findDarwinOS() (os, version, arch string) {
+	os = IOS
+	for _, prop := range p.list {
+		switch prop.Key {
+		case "x86_64":
+			os, arch = MacOS, "x64"
+		case "arm64":
+			os, arch = MacOS, "arm64"
+		}
+	}
+	major := strings.SplitN(p.get("Darwin"), ".", 2)[0]
+	if v, ok := darwinVersions[major]; ok {
+		if os == MacOS {
+			return os, v[1], arch
+		}
+		return os, v[0], arch
+	}
+	return os, "", arch
+}
+
+// findCFNetworkApp returns name and version of the app sending the request,
+// which is the first token with version before CFNetwork
+func (p properties) findCFNetworkApp() (name, version string) {
+	for _, prop := range p.list {
+		if prop.Key == "CFNetwork" {
+			break
+		}
+		if prop.Value != "" {
+			if s, err := url.PathUnescape(prop.Key); err == nil {
+				return s, prop.Value
+			}
+			return prop.Key, prop.Value
+		}
+	}
+	return "", ""
+}
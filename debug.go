@@ -0,0 +1,66 @@
+package useragent
+
+// Detection stages reported in DebugInfo.Stage
+const (
+	StageBuiltin  = "builtin"  // builtin detection
+	StageFallback = "fallback" // user agent is not recognized, name is picked from the remaining tokens
+	StageExtended = "extended" // extended browsers, see EnableExtendedBrowsers
+	StageRule     = "rule"     // rule loaded with LoadRules
+	StageMatcher  = "matcher"  // matcher registered with AddMatcher
+)
+
+// DebugInfo records how the user agent was detected, for triaging misparses
+// without reading the source
+type DebugInfo struct {
+	Stage        string   // detection stage which set the name, like StageBuiltin
+	Case         string   // token which decided the name, or rule and matcher description
+	VersionToken string   // token which provided the version, empty if version is not a token value
+	Tokens       []string // tokens used for detection, as key/value
+	Discarded    []string // tokens skipped as ignored or removed by token filters
+}
+
+// EnableDebugInfo enables DebugInfo in the parse results. It slows down
+// parsing and should be used only for troubleshooting.
+func (p *Parser) EnableDebugInfo() {
+	p.debug = true
+}
+
+// debugInfo returns debug info of the detected name and version
+func (p *Parser) debugInfo(tokens properties, name, version string, stage string, index int) *DebugInfo {
+	d := &DebugInfo{
+		Stage:     stage,
+		Discarded: tokens.discarded,
+	}
+	for _, prop := range tokens.list {
+		if prop.Value == "" {
+			d.Tokens = append(d.Tokens, prop.Key)
+		} else {
+			d.Tokens = append(d.Tokens, prop.Key+"/"+prop.Value)
+		}
+		if d.VersionToken == "" && version != "" && prop.Value == version {
+			d.VersionToken = prop.Key
+		}
+		if d.Case == "" && prop.Key == name {
+			d.Case = prop.Key
+		}
+	}
+	if d.Case == "" {
+		d.Case = d.VersionToken
+	}
+
+	switch stage {
+	case StageRule:
+		r := p.rules[index]
+		switch {
+		case r.Token != "":
+			d.Case = "token " + r.Token
+		case r.Prefix != "":
+			d.Case = "prefix " + r.Prefix
+		default:
+			d.Case = "regex " + r.Regex
+		}
+	case StageMatcher:
+		d.Case = "regex " + p.matchers[index].re.String()
+	}
+	return d
+}
@@ -0,0 +1,242 @@
+package useragent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DeviceType is the single device classification of the user agent.
+// Mobile, Tablet and Desktop flags are derived from it.
+type DeviceType int
+
+// Device types
+const (
+	DeviceUnknown DeviceType = iota
+	DevicePhone
+	DeviceTablet
+	DeviceDesktop
+	DeviceTV
+	DeviceConsole
+	DeviceWearable
+	DeviceEmbedded
+	DeviceBot
+	DeviceHeadset
+)
+
+// String returns device type name
+func (d DeviceType) String() string {
+	switch d {
+	case DevicePhone:
+		return "phone"
+	case DeviceTablet:
+		return "tablet"
+	case DeviceDesktop:
+		return "desktop"
+	case DeviceTV:
+		return "tv"
+	case DeviceConsole:
+		return "console"
+	case DeviceWearable:
+		return "wearable"
+	case DeviceEmbedded:
+		return "embedded"
+	case DeviceBot:
+		return "bot"
+	case DeviceHeadset:
+		return "headset"
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler, device type is encoded by name
+func (d DeviceType) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for device type names
+func (d *DeviceType) UnmarshalText(b []byte) error {
+	for t := DeviceUnknown; t <= DeviceHeadset; t++ {
+		if t.String() == string(b) {
+			*d = t
+			return nil
+		}
+	}
+	return fmt.Errorf("useragent: unknown device type %q", b)
+}
+
+// DeviceType returns device type of the user agent. Bots are reported as
+// DeviceBot, while Mobile, Tablet and Desktop flags still describe the device
+// the bot is emulating (e.g. Googlebot smartphone).
+func (ua UserAgent) DeviceType() DeviceType {
+	if ua.Bot {
+		return DeviceBot
+	}
+	if ua.deviceType != DeviceUnknown {
+		return ua.deviceType
+	}
+	// UserAgent not created by Parse
+	switch {
+	case ua.Tablet:
+		return DeviceTablet
+	case ua.Mobile:
+		return DevicePhone
+	case ua.Desktop:
+		return DeviceDesktop
+	}
+	return DeviceUnknown
+}
+
+// SetDeviceType sets device type and Mobile, Tablet and Desktop flags derived
+// from it, e.g. when UserAgent is restored from storage. DeviceBot sets Bot.
+func (ua *UserAgent) SetDeviceType(d DeviceType) {
+	if d == DeviceBot {
+		ua.Bot = true
+		return
+	}
+	ua.deviceType = d
+	ua.setDeviceType()
+}
+
+// plainUserAgent is UserAgent without methods, encoded field by field
+type plainUserAgent UserAgent
+
+// userAgentJSON is UserAgent encoded with its device type, which is not
+// an exported field
+type userAgentJSON struct {
+	plainUserAgent
+	DeviceType DeviceType `json:",omitempty"`
+}
+
+// MarshalJSON encodes user agent fields and the device type, so device types
+// without a flag, like DeviceTV, are kept when the user agent is restored.
+// Bots are encoded with the device they emulate, DeviceType returns DeviceBot
+// for them.
+func (ua UserAgent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(userAgentJSON{plainUserAgent(ua), ua.deviceType})
+}
+
+// UnmarshalJSON decodes user agent encoded by MarshalJSON. Without the device
+// type, like in JSON encoded by older versions, it is derived from the flags.
+func (ua *UserAgent) UnmarshalJSON(b []byte) error {
+	var v userAgentJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*ua = UserAgent(v.plainUserAgent)
+	ua.deviceType = v.DeviceType
+	return nil
+}
+
+// setDeviceType resolves device type from the flags collected during parsing,
+// unless device type is already set by a rule, and derives the flags back from it.
+// Tablet takes precedence over mobile, and mobile over desktop.
+func (ua *UserAgent) setDeviceType() {
+	if ua.deviceType == DeviceUnknown {
+		switch {
+		case ua.Tablet:
+			ua.deviceType = DeviceTablet
+		case ua.Mobile:
+			ua.deviceType = DevicePhone
+		case ua.Desktop:
+			ua.deviceType = DeviceDesktop
+		}
+	}
+	ua.Mobile = ua.deviceType == DevicePhone
+	ua.Tablet = ua.deviceType == DeviceTablet
+	ua.Desktop = ua.deviceType == DeviceDesktop
+}
+
+// tvDevicePrefixes are model prefixes of Android TV devices and set-top boxes
+var tvDevicePrefixes = []string{"BRAVIA", "AFT", "SHIELD", "MIBOX", "MiBOX", "Chromecast"}
+
+// isTVDevice returns true for Android TV device models, like
+// "BRAVIA 4K GB", "AFTMM" (Fire TV) or "SHIELD Android TV"
+func isTVDevice(device string) bool {
+	for _, prefix := range tvDevicePrefixes {
+		if strings.HasPrefix(device, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(device, "Android TV")
+}
+
+// tabletPatterns are model patterns of Android tablets, matched at the start
+// of the model or of any word in it, like SM-T500 or Lenovo TB-X606F. Tab and
+// Pad are matched as whole words, so Padfone and Tablet Phone are not tablets.
+var tabletPatterns = []string{"SM-T", "SM-P", "SM-X", "GT-P", "TB-", "Tab ", "Pad ", "MatePad", "MediaPad"}
+
+// AddTabletPattern adds Android tablet model patterns to the builtin ones.
+// Pattern matches at the start of the device model or of any word in it,
+// so "SM-T" matches "SAMSUNG SM-T500". Pattern ending with space matches
+// whole word, so "Tab " matches "Galaxy Tab S8" and "Galaxy Tab", but not
+// "Tablet".
+func (p *Parser) AddTabletPattern(patterns ...string) {
+	p.tablets = append(p.tablets, patterns...)
+}
+
+// isTabletModel returns true if device model matches any of the builtin
+// or parser tablet patterns
+func (p *Parser) isTabletModel(device string) bool {
+	if device == "" {
+		return false
+	}
+	for _, patterns := range [2][]string{tabletPatterns, p.tablets} {
+		for _, pattern := range patterns {
+			if hasWordPrefix(device, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasWordPrefix returns true if s or any of its space separated words
+// starts with prefix. Prefix ending with space matches the last word too.
+func hasWordPrefix(s, prefix string) bool {
+	word := strings.TrimSuffix(prefix, " ")
+	for {
+		if strings.HasPrefix(s, prefix) || s == word {
+			return true
+		}
+		i := strings.IndexByte(s, ' ')
+		if i == -1 {
+			return false
+		}
+		s = s[i+1:]
+	}
+}
+
+// mobileTokens are tokens sent by phones, feature phones, tablets and e-readers,
+// and by iOS browsers, which keep them in desktop mode
+var mobileTokens = []string{Mobile, Android, "iPhone", "iPad", "iPod", "Windows Phone",
+	"BlackBerry", "Opera Mini", "KAIOS", "MIDP", "Series40", "Series60", "SymbianOS",
+	"Kindle", "Kobo", "PocketBook", "Sailfish", "postmarketOS", "Mobian", "Ubuntu; Tablet",
+	"CriOS", "FxiOS", "EdgiOS", "OPiOS"}
+
+// nonMobileTokens are sent along with mobile tokens by Android TVs and set-top
+// boxes, VR headsets, watches and consoles, which are not phones or tablets.
+// TV models are matched after "; " separator, since "AFT" (Fire TV) is too short
+// to be matched anywhere.
+var nonMobileTokens = []string{"; BRAVIA", "; AFT", "; SHIELD", "; MIBOX", "; MiBOX", "Chromecast",
+	"Android TV", "; Quest", "OculusBrowser", "RealityDevice", "Wear OS", "Apple Watch", "Nintendo"}
+
+// IsMobile returns true if user agent contains any of the phone or tablet
+// tokens, like Mobile, Android, iPhone, iPad or Windows Phone, and none of the
+// TV, headset, watch or console tokens, like BRAVIA, Quest, Wear OS or Apple
+// Watch. It is a quick scan without parsing, so unlike Parse it reports tablets
+// as mobile, and it doesn't recognize Samsung Internet and Miui in desktop mode,
+// or iPad in desktop mode without any of the iOS tokens.
+func IsMobile(userAgent string) bool {
+	for _, t := range nonMobileTokens {
+		if strings.Contains(userAgent, t) {
+			return false
+		}
+	}
+	for _, t := range mobileTokens {
+		if strings.Contains(userAgent, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package useragent
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// devicesCSV is the builtin device database
+//
+//go:embed data/devices.csv
+var devicesCSV string
+
+// defaultDeviceDB is used by parsers unless replaced with SetDeviceDB
+var defaultDeviceDB = mustLoadDeviceDB(devicesCSV)
+
+// DeviceInfo is vendor and marketing name of the device
+type DeviceInfo struct {
+	Vendor string // like "Samsung"
+	Model  string // marketing name, like "Galaxy S21"
+}
+
+// DeviceDB maps device model codes sent in user agents, like "SM-G991B",
+// to vendor and marketing name. Model codes are case insensitive and the
+// vendor prefix sent by some browsers, like "SAMSUNG SM-G991B", is ignored.
+// Zero value is an empty database.
+type DeviceDB struct {
+	devices map[string]DeviceInfo // by upper case model code
+	vendors map[string]bool       // upper case vendor names
+}
+
+// LoadDeviceDB reads device database in CSV format with model code, vendor and
+// marketing name columns, like "SM-G991B,Samsung,Galaxy S21". Lines starting
+// with # are comments.
+func LoadDeviceDB(r io.Reader) (*DeviceDB, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	db := &DeviceDB{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return db, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("useragent: device db: %v", err)
+		}
+		db.Add(rec[0], DeviceInfo{Vendor: rec[1], Model: rec[2]})
+	}
+}
+
+func mustLoadDeviceDB(s string) *DeviceDB {
+	db, err := LoadDeviceDB(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// deviceKey returns model code as it is stored in the database, upper
+// case without surrounding spaces
+func deviceKey(model string) string {
+	return strings.ToUpper(strings.TrimSpace(model))
+}
+
+// Lookup returns device info of the model code, case insensitive. Vendor
+// prefix of any vendor in the database, like "SAMSUNG SM-S918B" or
+// "Samsung-SM-S918B", is ignored.
+func (db *DeviceDB) Lookup(model string) (DeviceInfo, bool) {
+	key := deviceKey(model)
+	if d, ok := db.devices[key]; ok {
+		return d, true
+	}
+	if i := strings.IndexAny(key, " -_"); i > 0 && db.vendors[key[:i]] {
+		d, ok := db.devices[strings.TrimLeft(key[i:], " -_")]
+		return d, ok
+	}
+	return DeviceInfo{}, false
+}
+
+// Add adds or replaces device info of the model code
+func (db *DeviceDB) Add(model string, d DeviceInfo) {
+	if db.devices == nil {
+		db.devices = make(map[string]DeviceInfo)
+		db.vendors = make(map[string]bool)
+	}
+	db.devices[deviceKey(model)] = d
+	if d.Vendor != "" {
+		db.vendors[strings.ToUpper(d.Vendor)] = true
+	}
+}
+
+// DefaultDeviceDB returns copy of the builtin device database, which can be
+// extended and set with SetDeviceDB
+func DefaultDeviceDB() *DeviceDB {
+	db := &DeviceDB{
+		devices: make(map[string]DeviceInfo, len(defaultDeviceDB.devices)),
+		vendors: make(map[string]bool, len(defaultDeviceDB.vendors)),
+	}
+	for k, v := range defaultDeviceDB.devices {
+		db.devices[k] = v
+	}
+	for k := range defaultDeviceDB.vendors {
+		db.vendors[k] = true
+	}
+	return db
+}
+
+// SetDeviceDB sets device database used to resolve DeviceVendor and
+// DeviceModel. Setting nil disables the lookup.
+func (p *Parser) SetDeviceDB(db *DeviceDB) {
+	p.devices = db
+	p.noDevices = db == nil
+}
+
+// deviceDB returns device database of the parser
+func (p *Parser) deviceDB() *DeviceDB {
+	if p.devices == nil && !p.noDevices {
+		return defaultDeviceDB
+	}
+	return p.devices
+}
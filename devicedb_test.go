@@ -0,0 +1,107 @@
+package useragent_test
+
+import (
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+const galaxyS21 = "Mozilla/5.0 (Linux; Android 13; SM-G991B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+
+func TestDeviceDB(t *testing.T) {
+	agent := ua.Parse(galaxyS21)
+	if agent.DeviceVendor != "Samsung" || agent.DeviceModel != "Galaxy S21" || agent.Device != "SM-G991B" {
+		t.Error("builtin device db should resolve Samsung Galaxy S21, got", agent.DeviceVendor, agent.DeviceModel, agent.Device)
+	}
+
+	db, err := ua.LoadDeviceDB(strings.NewReader("# model,vendor,name\nSM-G991B, Samsung, Galaxy S21 5G\nAcme-1,Acme,Phone One\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := ua.NewParser()
+	p.SetDeviceDB(db)
+	tests := []struct {
+		ua, vendor, model string
+	}{
+		{galaxyS21, "Samsung", "Galaxy S21 5G"},
+		{"Mozilla/5.0 (Linux; Android 12; Acme-1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Acme", "Phone One"},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "", ""},
+	}
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.DeviceVendor != test.vendor || agent.DeviceModel != test.model {
+			t.Error("\n", test.ua, "\nshould be", test.vendor, test.model, "not", agent.DeviceVendor, agent.DeviceModel)
+		}
+	}
+
+	p.SetDeviceDB(nil)
+	if agent := p.Parse(galaxyS21); agent.DeviceModel != "" {
+		t.Error("device db should be disabled, got", agent.DeviceModel)
+	}
+
+	// default db copy can be extended without changing the builtin one
+	db = ua.DefaultDeviceDB()
+	db.Add("SM-G991B", ua.DeviceInfo{Vendor: "Samsung", Model: "Custom"})
+	if d, ok := db.Lookup("SM-S918B"); !ok || d.Model != "Galaxy S23 Ultra" {
+		t.Error("default db copy should contain builtin devices, got", d, ok)
+	}
+	if agent := ua.Parse(galaxyS21); agent.DeviceModel != "Galaxy S21" {
+		t.Error("builtin device db modified, got", agent.DeviceModel)
+	}
+
+	if _, err := ua.LoadDeviceDB(strings.NewReader("SM-G991B,Samsung\n")); err == nil {
+		t.Error("invalid device db should return error")
+	}
+}
+
+func TestDeviceDBLookup(t *testing.T) {
+	db := ua.DefaultDeviceDB()
+	for _, model := range []string{"SM-S918B", "sm-s918b", " SM-S918B ", "SAMSUNG SM-S918B", "Samsung-SM-S918B"} {
+		if d, ok := db.Lookup(model); !ok || d.Vendor != "Samsung" || d.Model != "Galaxy S23 Ultra" {
+			t.Errorf("%q should be Samsung Galaxy S23 Ultra, got %v %v", model, d, ok)
+		}
+	}
+	for _, model := range []string{"", "SAMSUNG", "Acme SM-S918B", "SM-S918"} {
+		if d, ok := db.Lookup(model); ok {
+			t.Errorf("%q should not be found, got %v", model, d)
+		}
+	}
+
+	agent := ua.Parse("Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36")
+	if agent.DeviceVendor != "Samsung" || agent.DeviceModel != "Galaxy S23 Ultra" {
+		t.Error("vendor prefix should be ignored, got", agent.Device, agent.DeviceVendor, agent.DeviceModel)
+	}
+
+	// zero value is an empty database
+	var empty ua.DeviceDB
+	if _, ok := empty.Lookup("Acme-1"); ok {
+		t.Error("empty device db should not find devices")
+	}
+	empty.Add("Acme-1", ua.DeviceInfo{Vendor: "Acme", Model: "Phone One"})
+	if d, ok := empty.Lookup("ACME ACME-1"); !ok || d.Model != "Phone One" {
+		t.Error("device added to zero value db should be found, got", d, ok)
+	}
+}
+
+func TestAppleModel(t *testing.T) {
+	tests := []struct {
+		ua, model string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 261.0.0.21.111 (iPhone14,5; iOS 16_1; en_US; en; scale=3.00; 1170x2532; 414013834)", "iPhone 13"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/19E258 [FBAN/FBIOS;FBDV/iPhone8,2;FBMD/iPhone;FBSN/iOS;FBSV/15.4.1;FBSS/3;FBID/phone;FBLC/fr_FR;FBOP/5]", "iPhone 6s Plus"},
+		{"Mozilla/5.0 (iPad; CPU OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 [FBAN/FBIOS;FBDV/iPad13,18;FBMD/iPad;FBSN/iPadOS;FBSV/16.5;FBSS/2;FBID/tablet;FBLC/en_US;FBOP/5]", "iPad (10th generation)"},
+		{"Spotify/8.8.84 iOS/17.2 (iPhone15,2)", "iPhone 14 Pro"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		vendor := "Apple"
+		if test.model == "" {
+			vendor = ""
+		}
+		if agent.DeviceModel != test.model || agent.DeviceVendor != vendor {
+			t.Error("\n", test.ua, "\nshould be", vendor, test.model, "not", agent.DeviceVendor, agent.DeviceModel)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package useragent
+
+import "strings"
+
+// linuxDistros are distribution tokens sent by Linux builds of browsers,
+// like "X11; Ubuntu; Linux x86_64", "Ubuntu/10.04" or "Ubuntu Chromium/37.0"
+var linuxDistros = []string{"Ubuntu", "Kubuntu", "Xubuntu", "Lubuntu", "Fedora", "Debian",
+	"Arch Linux", "Manjaro", "openSUSE", "SUSE", "CentOS", "Red Hat", "Gentoo", "Mageia", "Slackware"}
+
+// findDistro returns Linux distribution and its release, like Ubuntu 10.04.
+// Distribution tokens are removed, so they are not picked as browser name,
+// and distribution prefix is removed from the browser token, like Ubuntu
+// from "Ubuntu Chromium". Release is reported only when it is a plain
+// version, Fedora/3.6.3-4.fc13 is version of the Firefox package.
+func (p *properties) findDistro() (distro, version string) {
+	list := p.list[:0]
+	for _, prop := range p.list {
+		d, rest := matchDistro(prop.Key)
+		switch {
+		case d == "" || distro != "":
+			list = append(list, prop)
+			continue
+		case rest == "":
+			// Ubuntu or Ubuntu/10.04
+			distro = d
+			if isPlainVersion(prop.Value) {
+				version = prop.Value
+			}
+		case isPlainVersion(rest):
+			// Ubuntu 22.04
+			distro, version = d, rest
+		default:
+			// Ubuntu Chromium/37.0.2062.94
+			distro = d
+			list = append(list, property{Key: rest, Value: prop.Value})
+		}
+	}
+	p.list = list
+	return distro, version
+}
+
+// matchDistro returns distribution the key starts with, and the rest of the key
+func matchDistro(key string) (distro, rest string) {
+	for _, d := range linuxDistros {
+		if key == d {
+			return d, ""
+		}
+		if len(key) > len(d) && key[len(d)] == ' ' && strings.HasPrefix(key, d) {
+			return d, key[len(d)+1:]
+		}
+	}
+	return "", ""
+}
+
+// isPlainVersion returns true for version of digits and dots, like 22.04
+func isPlainVersion(s string) bool {
+	if s == "" || s[0] == '.' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
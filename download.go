@@ -0,0 +1,11 @@
+package useragent
+
+// downloadManagers are download managers and video downloaders,
+// grouped by the first letter of the hint
+var downloadManagers = [256][]rawClient{
+	'a': {{"aria2", "aria2", "aria2"}, {"axel/", "Axel", "Axel"}, {"axel ", "Axel", ""}},
+	'w': {{"wget2", "Wget2", "wget2"}},
+	'f': {{"free download manager", "Free Download Manager", "Free Download Manager"}, {"fdm/", "Free Download Manager", "FDM"}},
+	'j': {{"jdownloader", "JDownloader", "JDownloader"}},
+	'y': {{"youtube-dl", "youtube-dl", "youtube-dl"}, {"yt-dlp", "yt-dlp", "yt-dlp"}},
+}
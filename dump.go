@@ -0,0 +1,117 @@
+package useragent
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// rulesDump is JSON format of DumpRules, rules are in LoadRules format
+type rulesDump struct {
+	Rules    []Rule        `json:"rules"`
+	Matchers []matcherDump `json:"matchers"`
+	Ignore   []string      `json:"ignore"`  // tokens ignored by the parser, added with Ignore
+	Aliases  []aliasDump   `json:"aliases"` // name aliases, set with SetNameAlias
+	Builtin  builtinDump   `json:"builtin"`
+}
+
+type matcherDump struct {
+	Priority int    `json:"priority"`
+	Regex    string `json:"regex"`
+}
+
+type clientDump struct {
+	Hint string `json:"hint"` // case insensitive substring of the user agent
+	Name string `json:"name"`
+}
+
+// builtinDump lists data driven part of the builtin detection
+type builtinDump struct {
+	BotKeywords      []string     `json:"bot_keywords"`
+	BotDomains       []string     `json:"bot_domains"`
+	Scanners         []clientDump `json:"scanners"`
+	FeedReaders      []clientDump `json:"feed_readers"`
+	MediaPlayers     []clientDump `json:"media_players"`
+	DownloadManagers []clientDump `json:"download_managers"`
+	PackageManagers  []clientDump `json:"package_managers"`
+	DevTools         []clientDump `json:"dev_tools"`
+	Webhooks         []clientDump `json:"webhooks"`
+	AutomationTokens []string     `json:"automation_tokens"`
+	PrefetchTokens   []string     `json:"prefetch_tokens"`
+	Tools            []string     `json:"tools"`
+	MobileTokens     []string     `json:"mobile_tokens"`
+	TabletPatterns   []string     `json:"tablet_patterns"`
+	TVDevicePrefixes []string     `json:"tv_device_prefixes"`
+	Precedence       []string     `json:"precedence"`
+	ExtendedBrowsers []string     `json:"extended_browsers"`
+	FoldTokens       []string     `json:"case_insensitive_tokens"`
+	IgnoredTokens    []string     `json:"ignored_tokens"`
+}
+
+// DumpRules writes detection rules of the default parser as JSON, see Parser.DumpRules
+func DumpRules(w io.Writer) error {
+	return defaultParser().DumpRules(w)
+}
+
+// DumpRules writes detection rules of the parser as indented JSON, so they
+// can be audited without reading the source. The output contains rules
+// loaded with LoadRules, in the same format so it can be loaded back,
+// matcher patterns, ignored tokens, name aliases, and the builtin tables of
+// bot keywords and domains, scanners, feed readers, media players, download
+// and package managers, git clients, CI/CD agents and webhooks, automation,
+// prefetch and tool names, mobile tokens and tablet and TV device patterns,
+// including patterns added to the parser, and tokens matched case
+// insensitive and ignored.
+// Builtin browser and OS detection is code, so it is not listed. The output is
+// the same for the same parser configuration and release, sets and maps are
+// written in sorted order.
+func (p *Parser) DumpRules(w io.Writer) error {
+	d := rulesDump{
+		Rules:    append([]Rule{}, p.rules...),
+		Matchers: []matcherDump{},
+		Ignore:   sortedSet(p.ignore),
+		Aliases:  sortedAliases(p.aliases),
+		Builtin: builtinDump{
+			BotKeywords:      botKeywords,
+			BotDomains:       botDomains,
+			Scanners:         dumpClients(&scanners),
+			FeedReaders:      dumpClients(&feedReaders),
+			MediaPlayers:     dumpClients(&mediaPlayers),
+			DownloadManagers: dumpClients(&downloadManagers),
+			PackageManagers:  dumpClients(&packageManagers),
+			DevTools:         dumpClients(&devTools),
+			Webhooks:         dumpClients(&webhooks),
+			AutomationTokens: append(append([]string{}, automationClients...), "rod", "chromedp", "HeadlessChrome"),
+			PrefetchTokens:   prefetchAgents,
+			Tools:            toolNames,
+			MobileTokens:     mobileTokens,
+			TabletPatterns:   append(append([]string{}, tabletPatterns...), p.tablets...),
+			TVDevicePrefixes: tvDevicePrefixes,
+			Precedence:       p.precedence(),
+			ExtendedBrowsers: []string{},
+			FoldTokens:       dumpFoldTokens(),
+			IgnoredTokens:    sortedStrings(ignoredTokens),
+		},
+	}
+	for _, m := range p.matchers {
+		d.Matchers = append(d.Matchers, matcherDump{Priority: m.priority, Regex: m.re.String()})
+	}
+	if p.extended {
+		for _, b := range extendedBrowsers {
+			d.Builtin.ExtendedBrowsers = append(d.Builtin.ExtendedBrowsers, b.token)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// dumpClients returns clients of the table ordered by the hint first letter
+func dumpClients(clients *[256][]rawClient) []clientDump {
+	var d []clientDump
+	for _, group := range clients {
+		for _, c := range group {
+			d = append(d, clientDump{Hint: c.hint, Name: c.name})
+		}
+	}
+	return d
+}
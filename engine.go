@@ -0,0 +1,97 @@
+package useragent
+
+import "strings"
+
+// Browser engines, reported in Engine
+const (
+	Blink    = "Blink"
+	WebKit   = "WebKit"
+	Gecko    = "Gecko"
+	Trident  = "Trident"
+	EdgeHTML = "EdgeHTML"
+	Presto   = "Presto"
+)
+
+// inAppNames are app names of in-app browsers, reported in AppName
+var inAppNames = map[string]string{
+	FacebookApp:  "Facebook",
+	InstagramApp: "Instagram",
+	TiktokApp:    "TikTok",
+	NaverApp:     "Naver",
+	DaumApp:      "Daum",
+	KakaoTalkApp: "KakaoTalk",
+	LineApp:      "LINE",
+	WeChatApp:    "WeChat",
+	QQApp:        "QQ",
+	WeiboApp:     "Weibo",
+	AlipayApp:    "Alipay",
+	BaiduApp:     "Baidu",
+}
+
+// evergreenChrome is Chrome version placeholder in user agents of crawlers
+// rendering with the current Chrome, like Googlebot documents its user agents
+const evergreenChrome = "W.X.Y.Z"
+
+// findEngine returns browser engine and its version. All browsers on iOS
+// use WebKit, Chrome 28+ uses Blink and Gecko version is sent in rv token.
+func (p properties) findEngine(os string) (name, version string) {
+	chrome := p.getWord(Chrome) // like "Brave Chrome/87.0.4280.101"
+	if chrome == "" {
+		chrome = p.get("HeadlessChrome")
+	}
+	switch {
+	case p.exists(Trident):
+		return Trident, p.get(Trident)
+	case p.exists(Presto):
+		return Presto, p.get(Presto)
+	case p.exists(Edge) && chrome != "":
+		return EdgeHTML, p.get(Edge)
+	case os == IOS && p.exists("AppleWebKit"):
+		return WebKit, p.get("AppleWebKit")
+	case majorNo(chrome) >= 28, chrome == evergreenChrome:
+		return Blink, chrome
+	case p.exists("AppleWebKit"):
+		return WebKit, p.get("AppleWebKit")
+	case p.exists(Gecko):
+		for _, prop := range p.list {
+			if strings.HasPrefix(prop.Key, "rv ") {
+				return Gecko, prop.Key[len("rv "):]
+			}
+		}
+		return Gecko, ""
+	}
+	return "", ""
+}
+
+// majorNo returns major version number, or 0 if unknown
+func majorNo(version string) int {
+	n := 0
+	for _, c := range []byte(majorVersion(version)) {
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// setChain sets the app, browser and engine of the user agent, so in-app
+// browsers report the app, the browser it embeds and the engine separately
+// from the Name, like Instagram, Chrome and Blink on Android
+func (ua *UserAgent) setChain(tokens properties) {
+	ua.Engine, ua.EngineVersion = tokens.findEngine(ua.OS)
+	if app, ok := inAppNames[ua.Name]; ok && ua.AppName == "" {
+		ua.AppName, ua.AppVersion = app, ua.Version
+	}
+
+	switch {
+	case ua.Bot || ua.Tool || ua.Category != "" || ua.Name == ua.Raw:
+		// not a browser, or not recognized
+	case ua.AppName != "" && ua.Electron == "":
+		// in-app browser embeds WebView, Chrome on Android and Safari on iOS
+		if tokens.exists(Chrome) {
+			ua.BrowserName, ua.BrowserVersion = Chrome, tokens.get(Chrome)
+		} else if ua.Engine == WebKit {
+			ua.BrowserName, ua.BrowserVersion = Safari, tokens.get(Version)
+		}
+	default:
+		ua.BrowserName, ua.BrowserVersion = ua.Name, ua.Version
+	}
+}
@@ -0,0 +1,43 @@
+package useragent
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+)
+
+// Equal reports whether a and b are the same parse result, ignoring the raw
+// user agent string, raw tokens and debug info, so different user agent
+// strings of the same browser, OS and device are equal
+func Equal(a, b UserAgent) bool {
+	return reflect.DeepEqual(a.normalized(), b.normalized())
+}
+
+// Hash returns stable FNV-1a hash of the fields compared by Equal, so equal
+// user agents have the same hash in every process. Zero fields are skipped,
+// hashes don't change when new fields are added to UserAgent.
+func (ua UserAgent) Hash() uint64 {
+	h := fnv.New64a()
+	v := reflect.ValueOf(ua.normalized())
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || v.Field(i).IsZero() {
+			continue
+		}
+		fmt.Fprintf(h, "%s=%v\x00", f.Name, v.Field(i).Interface())
+	}
+	if ua.deviceType != DeviceUnknown {
+		fmt.Fprintf(h, "DeviceType=%s\x00", ua.deviceType)
+	}
+	return h.Sum64()
+}
+
+// normalized returns ua without the fields ignored by Equal
+func (ua UserAgent) normalized() UserAgent {
+	ua.Raw, ua.String, ua.Debug, ua.Tokens = "", "", nil, nil
+	if len(ua.Anomalies) == 0 {
+		ua.Anomalies = nil
+	}
+	return ua
+}
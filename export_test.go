@@ -0,0 +1,14 @@
+package useragent
+
+// PooledBufferCap returns capacity of the tokenizer buffers reused by the parser
+func PooledBufferCap(p *Parser) int {
+	b := p.getBuffers()
+	defer p.putBuffers(b)
+	if b.val.Cap() > b.buff.Cap() {
+		return b.val.Cap()
+	}
+	return b.buff.Cap()
+}
+
+// FindVersion exports findVersion for tests
+var FindVersion = findVersion
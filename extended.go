@@ -0,0 +1,60 @@
+package useragent
+
+// Extended browsers, detected only when enabled with Parser.EnableExtendedBrowsers
+const (
+	CocCoc      = "Coc Coc"
+	Maxthon     = "Maxthon"
+	Puffin      = "Puffin"
+	Aloha       = "Aloha"
+	Iron        = "Iron"
+	CentBrowser = "Cent Browser"
+	Browser360  = "360 Browser"
+)
+
+// extendedBrowser rule, if chromeVersion is set the version is taken from Chrome token
+type extendedBrowser struct {
+	token         string
+	name          string
+	chromeVersion bool
+}
+
+// extendedBrowsers is the long tail of Chromium based browsers. Browsers
+// sending unchanged Chrome or Safari user agent, like Arc or SigmaOS, can't
+// be detected.
+var extendedBrowsers = []extendedBrowser{
+	{"coc_coc_browser", CocCoc, false},
+	{"Maxthon", Maxthon, false},
+	{"MxBrowser", Maxthon, false},
+	{"Puffin", Puffin, false},
+	{"AlohaBrowser", Aloha, false},
+	{"Iron", Iron, false},
+	{"Iron Safari", Iron, true},
+	{"CentBrowser", CentBrowser, false},
+	{"QIHU 360SE", Browser360, true},
+	{"QIHU 360EE", Browser360, true},
+	{"360SE", Browser360, true},
+	{"360EE", Browser360, true},
+}
+
+// EnableExtendedBrowsers enables detection of the long tail of Chromium based
+// browsers, like Coc Coc, Maxthon, Puffin or 360 Browser, which are
+// not checked by default to keep the parsing fast
+func (p *Parser) EnableExtendedBrowsers() {
+	p.extended = true
+}
+
+// findExtendedBrowser returns name and version of the extended browser
+func (p properties) findExtendedBrowser() (name, version string) {
+	for _, b := range extendedBrowsers {
+		for _, prop := range p.list {
+			if prop.Key != b.token {
+				continue
+			}
+			if b.chromeVersion || prop.Value == "" {
+				return b.name, p.get(Chrome)
+			}
+			return b.name, prop.Value
+		}
+	}
+	return "", ""
+}
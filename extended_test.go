@@ -0,0 +1,38 @@
+package useragent_test
+
+import (
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestExtendedBrowsers(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) coc_coc_browser/117.0.180 Chrome/111.0.5563.180 Safari/537.36", ua.CocCoc, "117.0.180"},
+		{"Mozilla/5.0 (Windows NT 10.0; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Maxthon/5.3.8.2000 Chrome/62.0.3202.94 Safari/537.36", ua.Maxthon, "5.3.8.2000"},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.114 Mobile Safari/537.36 Puffin/9.7.2.51014AP", ua.Puffin, "9.7.2.51014AP"},
+		{"Mozilla/5.0 (Linux; Android 11; SM-A515F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.5615.136 Mobile Safari/537.36 AlohaBrowser/5.10.4", ua.Aloha, "5.10.4"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Iron Safari/537.36", ua.Iron, "112.0.0.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.198 Safari/537.36 CentBrowser/4.3.9.248", ua.CentBrowser, "4.3.9.248"},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/63.0.3239.132 Safari/537.36 QIHU 360SE", ua.Browser360, "63.0.3239.132"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0"},
+	}
+
+	p := ua.NewParser()
+	p.EnableExtendedBrowsers()
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Error("\n", test.ua, "\nName should be", test.name, test.version, "not", agent.Name, agent.Version)
+		}
+	}
+
+	// extended browsers are not detected by default
+	if agent := ua.Parse(tests[6].ua); agent.Name != ua.Chrome {
+		t.Error("extended browser detected by default parser", agent.Name)
+	}
+}
@@ -0,0 +1,13 @@
+package useragent
+
+// feedReaders are RSS and Atom readers and podcast clients,
+// grouped by the first letter of the hint
+var feedReaders = [256][]rawClient{
+	'f': {{"feedly", "Feedly", "Feedly"}},
+	'i': {{"inoreader", "Inoreader", "Inoreader"}},
+	'n': {{"newsblur", "NewsBlur", ""}},
+	'm': {{"miniflux", "Miniflux", "Miniflux"}},
+	'o': {{"overcast", "Overcast", "Overcast"}},
+	'p': {{"pocketcasts", "Pocket Casts", "PocketCasts"}, {"pocket casts", "Pocket Casts", "PocketCasts"}},
+	'g': {{"gpodder", "gPodder", "gPodder"}},
+}
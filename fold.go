@@ -0,0 +1,43 @@
+package useragent
+
+import "strings"
+
+// foldTokens are tokens matched case insensitive, since they are sent in
+// varying case by different versions and clones, like "bingbot" and "BingBot".
+// Tokens are rewritten to the case listed here before detection, so the
+// detection compares them exactly. Grouped by the first letter, lower case.
+var foldTokens = [256][]string{
+	'a': {Applebot},
+	'b': {"bingbot", "Bytespider"},
+	'f': {FacebookExternalHit},
+	'g': {Googlebot},
+	'h': {"HuaweiBrowser", "HeadlessChrome"},
+	's': {"SamsungBrowser"},
+	't': {Twitterbot},
+	'x': {"XiaoMi"},
+	'y': {YandexBot, "YaBrowser"},
+}
+
+// foldToken returns token in the case listed in foldTokens, or key as is.
+// Tokens sent in the listed case, the common case, are found by the exact
+// comparison, other tokens only by the length check in the group.
+func foldToken(key string) string {
+	if key == "" {
+		return key
+	}
+	for _, t := range foldTokens[key[0]|0x20] {
+		if len(t) == len(key) && (t == key || strings.EqualFold(t, key)) {
+			return t
+		}
+	}
+	return key
+}
+
+// dumpFoldTokens returns case insensitive tokens ordered by the first letter
+func dumpFoldTokens() []string {
+	var d []string
+	for _, group := range foldTokens {
+		d = append(d, group...)
+	}
+	return d
+}
@@ -0,0 +1,165 @@
+package useragent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// default versions used by Format when version is not set
+const (
+	formatChromeVersion  = "120.0.0.0"
+	formatFirefoxVersion = "121.0"
+	formatSafariVersion  = "17.2"
+	formatOperaVersion   = "106.0.0.0"
+	formatAndroidVersion = "10"
+	formatAndroidDevice  = "K"
+	formatAndroidTablet  = "SM-X710"
+	formatIOSVersion     = "17.2"
+	formatMacOSVersion   = "10.15.7"
+	formatWindowsVersion = "10.0"
+	formatChromeOSVer    = "14541.0.0"
+)
+
+// Format returns realistic user agent string for the browser Name, Version,
+// OS, OSVersion and Device of ua, so the UserAgent struct can be used as a spec
+// for generating test traffic. Parsing the result returns the same Name,
+// Version, OS and Device. Empty fields are filled with recent defaults, OS
+// defaults to Android if Mobile is set, iOS if Tablet is set (iPad), and Windows otherwise.
+// Android tablets without Device get a Galaxy Tab model, so they parse as tablets.
+//
+// Supported browsers are Chrome, Firefox, Safari, Edge and Opera, supported
+// OSes are Windows, macOS, Linux, ChromeOS, Android and iOS.
+func Format(ua UserAgent) (string, error) {
+	os := ua.OS
+	if os == "" {
+		switch {
+		case ua.Tablet:
+			os = IOS
+		case ua.Mobile:
+			os = Android
+		default:
+			os = Windows
+		}
+	}
+	name := ua.Name
+	if name == "" {
+		name = Chrome
+		if os == IOS || os == MacOS {
+			name = Safari
+		}
+	}
+
+	platform, err := formatPlatform(os, ua.OSVersion, ua.Device, ua.Tablet)
+	if err != nil {
+		return "", err
+	}
+	mobile := (os == Android || os == IOS) && !ua.Tablet
+
+	switch name {
+	case Chrome, Edge, Opera:
+		return formatChromium(name, ua.Version, os, platform, mobile), nil
+
+	case Firefox:
+		version := defaultString(ua.Version, formatFirefoxVersion)
+		major := strings.SplitN(version, ".", 2)[0]
+		switch os {
+		case IOS:
+			return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/" + version + " Mobile/15E148 Safari/605.1.15", nil
+		case Android:
+			// Firefox doesn't send the device, only Mobile or Tablet
+			platform = "Android " + defaultString(ua.OSVersion, formatAndroidVersion)
+			if ua.Device != "" {
+				platform += "; " + ua.Device
+			}
+			if ua.Tablet {
+				platform += "; Tablet"
+			} else {
+				platform += "; Mobile"
+			}
+			return "Mozilla/5.0 (" + platform + "; rv:" + major + ".0) Gecko/" + major + ".0 Firefox/" + version, nil
+		}
+		return "Mozilla/5.0 (" + platform + "; rv:" + major + ".0) Gecko/20100101 Firefox/" + version, nil
+
+	case Safari:
+		version := defaultString(ua.Version, formatSafariVersion)
+		switch os {
+		case IOS:
+			return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) Version/" + version + " Mobile/15E148 Safari/604.1", nil
+		case MacOS:
+			return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) Version/" + version + " Safari/605.1.15", nil
+		}
+		return "", fmt.Errorf("useragent: Safari is not available on %s", os)
+	}
+	return "", fmt.Errorf("useragent: unsupported browser %q", name)
+}
+
+// formatPlatform returns platform token of the user agent, without parentheses
+func formatPlatform(os, version, device string, tablet bool) (string, error) {
+	switch os {
+	case Windows:
+		return "Windows NT " + defaultString(version, formatWindowsVersion) + "; Win64; x64", nil
+	case MacOS:
+		return "Macintosh; Intel Mac OS X " + strings.Replace(defaultString(version, formatMacOSVersion), ".", "_", -1), nil
+	case Linux:
+		return "X11; Linux x86_64", nil
+	case ChromeOS:
+		return "X11; CrOS x86_64 " + defaultString(version, formatChromeOSVer), nil
+	case Android:
+		// reduced "K" model can't be told apart from a phone without Mobile token
+		if tablet && device == "" {
+			device = formatAndroidTablet
+		}
+		return "Linux; Android " + defaultString(version, formatAndroidVersion) + "; " + defaultString(device, formatAndroidDevice), nil
+	case IOS:
+		v := strings.Replace(defaultString(version, formatIOSVersion), ".", "_", -1)
+		if tablet || device == "iPad" {
+			return "iPad; CPU OS " + v + " like Mac OS X", nil
+		}
+		return "iPhone; CPU iPhone OS " + v + " like Mac OS X", nil
+	}
+	return "", fmt.Errorf("useragent: unsupported OS %q", os)
+}
+
+// formatChromium returns user agent of Chrome and Chromium based browsers
+func formatChromium(name, version, os, platform string, mobile bool) string {
+	chrome := formatChromeVersion
+	if name == Chrome && version != "" {
+		chrome = version
+	}
+
+	if os == IOS {
+		s := "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) "
+		switch name {
+		case Edge:
+			s += "Version/17.0 EdgiOS/" + defaultString(version, formatChromeVersion)
+		case Opera:
+			s += "Version/17.0 OPiOS/" + defaultString(version, formatOperaVersion)
+		default:
+			s += "CriOS/" + chrome
+		}
+		return s + " Mobile/15E148 Safari/604.1"
+	}
+
+	s := "Mozilla/5.0 (" + platform + ") AppleWebKit/537.36 (KHTML, like Gecko) Chrome/" + chrome
+	if mobile {
+		s += " Mobile"
+	}
+	s += " Safari/537.36"
+	switch name {
+	case Edge:
+		if os == Android {
+			return s + " EdgA/" + defaultString(version, formatChromeVersion)
+		}
+		return s + " Edg/" + defaultString(version, formatChromeVersion)
+	case Opera:
+		return s + " OPR/" + defaultString(version, formatOperaVersion)
+	}
+	return s
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package useragent_test
+
+import (
+	"fmt"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestFormat(t *testing.T) {
+	specs := []ua.UserAgent{
+		{},
+		{Mobile: true},
+		{Tablet: true},
+		{Name: ua.Chrome, Version: "119.0.6045.105", OS: ua.Windows, OSVersion: "10.0"},
+		{Name: ua.Chrome, Version: "119.0.6045.105", OS: ua.MacOS, OSVersion: "10.15.7"},
+		{Name: ua.Chrome, Version: "119.0.6045.105", OS: ua.Linux},
+		{Name: ua.Chrome, Version: "119.0.6045.105", OS: ua.ChromeOS},
+		{Name: ua.Chrome, Version: "119.0.6045.163", OS: ua.Android, OSVersion: "13", Device: "SM-S918B"},
+		{OS: ua.Android, Tablet: true},
+		{Name: ua.Chrome, Version: "119.0.6045.169", OS: ua.IOS, OSVersion: "17.1.1", Device: "iPhone"},
+		{Name: ua.Firefox, Version: "120.0", OS: ua.Windows},
+		{Name: ua.Firefox, Version: "120.0", OS: ua.Linux},
+		{Name: ua.Firefox, Version: "120.0", OS: ua.Android, OSVersion: "13"},
+		{Name: ua.Firefox, OS: ua.Android, Device: "Pixel 7"},
+		{Name: ua.Firefox, OS: ua.Android, Tablet: true},
+		{Name: ua.Firefox, Version: "120.0", OS: ua.IOS, OSVersion: "17.1"},
+		{Name: ua.Safari, Version: "17.1", OS: ua.MacOS, OSVersion: "10.15.7"},
+		{Name: ua.Safari, Version: "17.1", OS: ua.IOS, OSVersion: "17.1", Device: "iPad"},
+		{Name: ua.Edge, Version: "119.0.2151.97", OS: ua.Windows},
+		{Name: ua.Edge, Version: "119.0.2151.96", OS: ua.Android, Device: "Pixel 7"},
+		{Name: ua.Edge, Version: "119.2151.96", OS: ua.IOS},
+		{Name: ua.Opera, Version: "105.0.0.0", OS: ua.Windows},
+		{Name: ua.Opera, Version: "105.0.0.0", OS: ua.MacOS},
+	}
+	for _, spec := range specs {
+		s, err := ua.Format(spec)
+		if err != nil {
+			t.Errorf("%+v: %v", spec, err)
+			continue
+		}
+		agent := ua.Parse(s)
+		if spec.Name != "" && agent.Name != spec.Name ||
+			spec.Version != "" && agent.Version != spec.Version ||
+			spec.OS != "" && agent.OS != spec.OS ||
+			spec.OSVersion != "" && agent.OSVersion != spec.OSVersion ||
+			spec.Device != "" && agent.Device != spec.Device ||
+			spec.Mobile && !agent.Mobile ||
+			spec.Tablet && !agent.Tablet ||
+			spec.Tablet && agent.Mobile {
+			t.Errorf("\n%s\nparsed as %+v\nspec %+v", s, agent, spec)
+		}
+	}
+}
+
+func TestFormatUnsupported(t *testing.T) {
+	for _, spec := range []ua.UserAgent{
+		{Name: "Netscape"},
+		{Name: ua.Chrome, OS: "AmigaOS"},
+		{Name: ua.Safari, OS: ua.Windows},
+	} {
+		if s, err := ua.Format(spec); err == nil {
+			t.Errorf("%+v: expected error, got %s", spec, s)
+		}
+	}
+}
+
+func ExampleFormat() {
+	s, _ := ua.Format(ua.UserAgent{Name: ua.Firefox, Version: "120.0", OS: ua.Linux})
+	fmt.Println(s)
+	// Output: Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0
+}
@@ -0,0 +1,16 @@
+package useragent
+
+// devTools are git clients and CI/CD agents, grouped by the first letter
+// of the hint
+var devTools = [256][]rawClient{
+	'g': {{"git/", "git", "git"}, {"gitlab-runner/", "GitLab Runner", "GitLab-Runner"}, {"gitlab-runner ", "GitLab Runner", "gitlab-runner"}},
+	'j': {{"jgit/", "JGit", "JGit"}, {"jenkins/", "Jenkins", "Jenkins"}},
+	'a': {{"argocd/", "Argo CD", "ArgoCD"}, {"argocd-", "Argo CD", "argocd"}},
+}
+
+// webhooks are repository hosting services calling webhooks, grouped by
+// the first letter of the hint
+var webhooks = [256][]rawClient{
+	'g': {{"github-hookshot/", "GitHub-Hookshot", "GitHub-Hookshot"}},
+	'b': {{"bitbucket-webhooks/", "Bitbucket-Webhooks", "Bitbucket-Webhooks"}},
+}
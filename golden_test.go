@@ -0,0 +1,152 @@
+package useragent_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// goldenFile holds Parse results of the whole corpus
+var goldenFile = filepath.Join("testdata", "golden", "corpus.jsonl")
+
+// golden is snapshot of parse result, Raw, String and parsed version
+// numbers are omitted as they are derived from other fields
+type golden struct {
+	UA           string     `json:"ua"`
+	Name         string     `json:"name,omitempty"`
+	Version      string     `json:"version,omitempty"`
+	OS           string     `json:"os,omitempty"`
+	OSVersion    string     `json:"os_version,omitempty"`
+	OSDistro     string     `json:"os_distro,omitempty"`
+	OSDistroVer  string     `json:"os_distro_version,omitempty"`
+	Device       string     `json:"device,omitempty"`
+	DeviceVendor string     `json:"device_vendor,omitempty"`
+	DeviceModel  string     `json:"device_model,omitempty"`
+	DeviceType   string     `json:"device_type"`
+	Bot          bool       `json:"bot,omitempty"`
+	BotReason    string     `json:"bot_reason,omitempty"`
+	BotContact   string     `json:"bot_contact,omitempty"`
+	Tool         bool       `json:"tool,omitempty"`
+	TextBrowser  bool       `json:"text_browser,omitempty"`
+	Prefetch     bool       `json:"prefetch,omitempty"`
+	Automation   string     `json:"automation_tool,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	Truncated    bool       `json:"truncated,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	URLs         []string   `json:"urls,omitempty"`
+	Arch         string     `json:"arch,omitempty"`
+	Locale       string     `json:"locale,omitempty"`
+	Region       string     `json:"region,omitempty"`
+	AndroidBuild string     `json:"android_build,omitempty"`
+	HMSCore      string     `json:"hms_core,omitempty"`
+	WebView      bool       `json:"webview,omitempty"`
+	EReader      bool       `json:"ereader,omitempty"`
+	MaybeIPad    bool       `json:"maybe_ipad,omitempty"`
+	DesktopMode  bool       `json:"desktop_mode,omitempty"`
+	AppName      string     `json:"app_name,omitempty"`
+	AppVersion   string     `json:"app_version,omitempty"`
+	Browser      string     `json:"browser,omitempty"`
+	BrowserVer   string     `json:"browser_version,omitempty"`
+	Engine       string     `json:"engine,omitempty"`
+	EngineVer    string     `json:"engine_version,omitempty"`
+	Electron     string     `json:"electron,omitempty"`
+	Screen       *ua.Screen `json:"screen,omitempty"`
+	Anomalies    []string   `json:"anomalies,omitempty"`
+}
+
+func newGolden(s string) golden {
+	agent := ua.Parse(s)
+	g := golden{
+		UA:           s,
+		Name:         agent.Name,
+		Version:      agent.Version,
+		OS:           agent.OS,
+		OSVersion:    agent.OSVersion,
+		OSDistro:     agent.OSDistro,
+		OSDistroVer:  agent.OSDistroVersion,
+		Device:       agent.Device,
+		DeviceVendor: agent.DeviceVendor,
+		DeviceModel:  agent.DeviceModel,
+		DeviceType:   agent.DeviceType().String(),
+		Bot:          agent.Bot,
+		BotReason:    agent.BotReason,
+		BotContact:   agent.BotContact,
+		Tool:         agent.Tool,
+		TextBrowser:  agent.TextBrowser,
+		Prefetch:     agent.Prefetch,
+		Automation:   agent.AutomationTool,
+		Category:     agent.Category,
+		Truncated:    agent.Truncated,
+		URL:          agent.URL,
+		URLs:         agent.URLs,
+		Arch:         agent.Arch,
+		Locale:       agent.Locale,
+		Region:       agent.Region,
+		AndroidBuild: agent.AndroidBuild,
+		HMSCore:      agent.HMSCore,
+		WebView:      agent.WebView,
+		EReader:      agent.EReader,
+		MaybeIPad:    agent.MaybeIPad,
+		DesktopMode:  agent.DesktopMode,
+		AppName:      agent.AppName,
+		AppVersion:   agent.AppVersion,
+		Browser:      agent.BrowserName,
+		BrowserVer:   agent.BrowserVersion,
+		Engine:       agent.Engine,
+		EngineVer:    agent.EngineVersion,
+		Electron:     agent.Electron,
+		Anomalies:    agent.Anomalies,
+	}
+	if agent.Screen != (ua.Screen{}) {
+		g.Screen = &agent.Screen
+	}
+	return g
+}
+
+// TestGolden compares Parse results of the whole corpus with the snapshot
+// in testdata/golden, so every change of the results is visible in the diff.
+// Run go test -run TestGolden -update to update the snapshot.
+func TestGolden(t *testing.T) {
+	var buf bytes.Buffer
+	for _, entry := range loadCorpus(t) {
+		b, err := json.Marshal(newGolden(entry.UserAgent))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(goldenFile, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err, "\nrun go test -run TestGolden -update to create it")
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	lines := strings.Split(strings.TrimSuffix(string(want), "\n"), "\n")
+	if len(got) != len(lines) {
+		t.Errorf("corpus has %d entries, golden file %d", len(got), len(lines))
+	}
+	for i := 0; i < len(got) && i < len(lines); i++ {
+		if got[i] != lines[i] {
+			t.Errorf("\nresult changed\nwas: %s\nnow: %s", lines[i], got[i])
+		}
+	}
+	if t.Failed() {
+		t.Log("if the changes are intended, run go test -run TestGolden -update and commit the snapshot")
+	}
+}
@@ -0,0 +1,124 @@
+package useragent
+
+import "strings"
+
+// ClientHints holds raw values of User-Agent Client Hints request headers.
+// Empty fields are ignored when merged.
+type ClientHints struct {
+	Mobile          string // Sec-CH-UA-Mobile, like ?1
+	Platform        string // Sec-CH-UA-Platform, like "Android"
+	PlatformVersion string // Sec-CH-UA-Platform-Version, like "13.0.0"
+	Model           string // Sec-CH-UA-Model, like "Pixel 7"
+	Brands          string // Sec-CH-UA, like "Chromium";v="120", "Google Chrome";v="120"
+	FullVersionList string // Sec-CH-UA-Full-Version-List, like "Google Chrome";v="120.0.6099.130"
+}
+
+// client hints platform names which differ from OS names
+var hintsPlatforms = map[string]string{
+	"Chrome OS":   ChromeOS,
+	"Chromium OS": ChromeOS,
+}
+
+// MergeHints merges client hints into the parsed user agent. Hints override
+// values from the user agent string, which are frozen or reduced by modern
+// browsers, and resolve MaybeIPad. Android platform with desktop user agent,
+// sent by Chrome on request desktop site, sets DesktopMode and the device type
+// from the model hint, tablet for tablet model patterns of the default parser
+// and phone otherwise. Without the model, the mobile hint ?1 sets phone, and
+// the device type is unknown for ?0, which phones and tablets both send in
+// desktop mode. Brands set the version from the full version list, and the name of Chromium based browsers reported as Chrome,
+// like Brave. BrowserName and BrowserVersion follow the name and version,
+// unless the browser is embedded by the app.
+func (ua *UserAgent) MergeHints(h ClientHints) {
+	if b := h.Browser(); b.Name != "" && b.Name != "Chromium" && (b.Name == ua.Name || ua.Name == Chrome) {
+		browser := ua.BrowserName != "" && ua.BrowserName == ua.Name
+		if b.Name != ua.Name {
+			ua.Name = b.Name
+			ua.Version = ""
+		}
+		// Sec-CH-UA sends only the major version
+		if h.FullVersionList != "" || ua.Version == "" {
+			ua.Version = b.Version
+		}
+		ua.VersionNo = parseVersion(ua.Version)
+		if browser {
+			ua.BrowserName, ua.BrowserVersion = ua.Name, ua.Version
+		}
+	}
+
+	var desktopMode bool
+	if platform := unquoteHint(h.Platform); platform != "" {
+		if os, ok := hintsPlatforms[platform]; ok {
+			platform = os
+		}
+		if platform != ua.OS {
+			// Chrome on Android requests desktop site with Linux user agent
+			if platform == Android && ua.DeviceType() == DeviceDesktop {
+				desktopMode = true
+				ua.DesktopMode = true
+			}
+			ua.OS = platform
+			ua.OSVersion = ""
+		}
+		if v := unquoteHint(h.PlatformVersion); v != "" {
+			if ua.OS == Windows {
+				v = windowsRelease(v, ua.OSVersion)
+			}
+			ua.OSVersion = v
+		}
+		ua.OSVersionNo = parseVersion(ua.OSVersion)
+	}
+
+	model := unquoteHint(h.Model)
+	if model != "" {
+		ua.Device = model
+	}
+
+	switch {
+	case strings.HasPrefix(model, "iPad"):
+		ua.OS = IOS
+		ua.deviceType = DeviceTablet
+		ua.MaybeIPad = false
+	case strings.HasPrefix(model, "iPhone"):
+		ua.OS = IOS
+		ua.deviceType = DevicePhone
+		ua.MaybeIPad = false
+	case h.Mobile == "?1" && ua.deviceType != DeviceTablet:
+		ua.deviceType = DevicePhone
+	case desktopMode && model != "":
+		if defaultParser().isTabletModel(model) {
+			ua.deviceType = DeviceTablet
+		} else {
+			ua.deviceType = DevicePhone
+		}
+	case desktopMode:
+		// phones request desktop site with ?0 too, like tablets by default,
+		// so the device type is unknown without the model
+		ua.deviceType = DeviceUnknown
+		ua.Desktop = false
+	case h.Mobile == "?0" && ua.MaybeIPad:
+		ua.deviceType = DeviceDesktop
+		ua.MaybeIPad = false
+		ua.DesktopMode = false
+	}
+	ua.setDeviceType()
+}
+
+// unquoteHint removes quotes from structured header string
+func unquoteHint(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
+
+// windowsRelease maps Windows platform version hint, which is not NT version,
+// to Windows release, "11" for major version 13 and above, "10" for 1 to 12.
+// Windows 7, 8 and 8.1 send 0.x, so NT version from user agent is kept.
+func windowsRelease(platformVersion, ntVersion string) string {
+	major := majorNo(platformVersion)
+	switch {
+	case major >= 13:
+		return "11"
+	case major >= 1:
+		return "10"
+	}
+	return ntVersion
+}
@@ -0,0 +1,166 @@
+package useragent_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+const (
+	macSafari   = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Safari/605.1.15"
+	iPadDesktop = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Mobile/15E148 Safari/604.1"
+	androidUA   = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+)
+
+func TestMaybeIPad(t *testing.T) {
+	if agent := ua.Parse(macSafari); agent.MaybeIPad || !agent.Desktop {
+		t.Error("macOS Safari should be desktop", agent)
+	}
+	if agent := ua.Parse(iPadDesktop); !agent.MaybeIPad || !agent.Tablet {
+		t.Error("iPad in desktop mode should be tablet with MaybeIPad", agent)
+	}
+}
+
+func TestMergeHints(t *testing.T) {
+	tests := []struct {
+		ua         string
+		hints      ua.ClientHints
+		os         string
+		osVersion  string
+		device     string
+		deviceType ua.DeviceType
+		maybeIPad  bool
+	}{
+		{iPadDesktop, ua.ClientHints{Model: `"iPad"`}, ua.IOS, "10.15.6", "iPad", ua.DeviceTablet, false},
+		{iPadDesktop, ua.ClientHints{Model: `"iPhone"`}, ua.IOS, "10.15.6", "iPhone", ua.DevicePhone, false},
+		{iPadDesktop, ua.ClientHints{Mobile: "?0", Platform: `"macOS"`}, ua.MacOS, "10.15.6", "", ua.DeviceDesktop, false},
+		{androidUA, ua.ClientHints{Mobile: "?1", Platform: `"Android"`, PlatformVersion: `"14.0.0"`, Model: `"Pixel 7"`}, ua.Android, "14.0.0", "Pixel 7", ua.DevicePhone, false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.ClientHints{Mobile: "?0", Platform: `"Windows"`, PlatformVersion: `"15.0.0"`}, ua.Windows, "11", "", ua.DeviceDesktop, false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.ClientHints{Mobile: "?0", Platform: `"Windows"`, PlatformVersion: `"10.0.0"`}, ua.Windows, "10", "", ua.DeviceDesktop, false},
+		{"Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36", ua.ClientHints{Mobile: "?0", Platform: `"Windows"`, PlatformVersion: `"0.1.0"`}, ua.Windows, "6.1", "", ua.DeviceDesktop, false},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.ClientHints{Platform: `"Chrome OS"`, PlatformVersion: `"15633.69.0"`}, ua.ChromeOS, "15633.69.0", "", ua.DeviceDesktop, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		agent.MergeHints(test.hints)
+		if agent.OS != test.os || agent.OSVersion != test.osVersion || agent.Device != test.device ||
+			agent.DeviceType() != test.deviceType || agent.MaybeIPad != test.maybeIPad {
+			t.Errorf("\n%s\n%+v\ngot %s %s %q %s MaybeIPad=%v", test.ua, test.hints, agent.OS, agent.OSVersion, agent.Device, agent.DeviceType(), agent.MaybeIPad)
+		}
+	}
+}
+
+func TestParseBrands(t *testing.T) {
+	tests := []struct {
+		header string
+		brands []ua.Brand
+		grease int
+	}{
+		{`"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`,
+			[]ua.Brand{{"Not_A Brand", "8"}, {"Chromium", "120"}, {"Google Chrome", "120"}}, 0},
+		{`"Chromium";v="118.0.5993.88", "Not=A?Brand";v="99.0.0.0", "Microsoft Edge";v="118.0.2088.61"`,
+			[]ua.Brand{{"Chromium", "118.0.5993.88"}, {"Not=A?Brand", "99.0.0.0"}, {"Microsoft Edge", "118.0.2088.61"}}, 1},
+		{`"Not;A,Brand";v="24" , "Brave";v="128";x=1,"Chromium" ;v="128"`,
+			[]ua.Brand{{"Not;A,Brand", "24"}, {"Brave", "128"}, {"Chromium", "128"}}, 0},
+		{`"Not\"A\\Brand";v="99", "Opera";v=106`,
+			[]ua.Brand{{`Not"A\Brand`, "99"}, {"Opera", "106"}}, 0},
+		{`"Chromium";v="120", Google Chrome;v="120"`, []ua.Brand{{"Chromium", "120"}}, -1},
+		{"", nil, -1},
+	}
+	for _, test := range tests {
+		brands := ua.ParseBrands(test.header)
+		if !reflect.DeepEqual(brands, test.brands) {
+			t.Errorf("\n%s\nbrands should be %v not %v", test.header, test.brands, brands)
+		}
+		if test.grease >= 0 && !brands[test.grease].Grease() {
+			t.Errorf("\n%s\n%q should be GREASE", test.header, brands[test.grease].Name)
+		}
+	}
+	for _, name := range []string{"Chromium", "Google Chrome", "Not A Brand Name", "Brand"} {
+		if (ua.Brand{Name: name}).Grease() {
+			t.Errorf("%q should not be GREASE", name)
+		}
+	}
+}
+
+func TestMergeHintsBrands(t *testing.T) {
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	tests := []struct {
+		ua      string
+		hints   ua.ClientHints
+		name    string
+		version string
+	}{
+		{chrome, ua.ClientHints{Brands: `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`}, ua.Chrome, "120.0.0.0"},
+		{chrome, ua.ClientHints{FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.130", "Google Chrome";v="120.0.6099.130"`}, ua.Chrome, "120.0.6099.130"},
+		{chrome, ua.ClientHints{Brands: `"Brave";v="120", "Chromium";v="120", "Not_A Brand";v="24"`}, "Brave", "120"},
+		{chrome, ua.ClientHints{FullVersionList: `"Brave";v="120.1.61.109", "Chromium";v="120.0.6099.217", "Not_A Brand";v="24.0.0.0"`}, "Brave", "120.1.61.109"},
+		{chrome, ua.ClientHints{Brands: `"Chromium";v="120", "Not_A Brand";v="24"`}, ua.Chrome, "120.0.0.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36 Edg/118.0.2088.61",
+			ua.ClientHints{FullVersionList: `"Chromium";v="118.0.5993.88", "Not=A?Brand";v="99.0.0.0", "Microsoft Edge";v="118.0.2088.61"`}, ua.Edge, "118.0.2088.61"},
+		{macSafari, ua.ClientHints{Brands: `"Google Chrome";v="120"`}, ua.Safari, "13.1.2"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		agent.MergeHints(test.hints)
+		if agent.Name != test.name || agent.Version != test.version || agent.VersionNo.Major == 0 {
+			t.Errorf("\n%s\n%+v\nshould be %s %s not %s %s", test.ua, test.hints, test.name, test.version, agent.Name, agent.Version)
+		}
+		if agent.BrowserName != test.name || agent.BrowserVersion != test.version {
+			t.Errorf("\n%s\n%+v\nbrowser should be %s %s not %s %s", test.ua, test.hints, test.name, test.version, agent.BrowserName, agent.BrowserVersion)
+		}
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("User-Agent", androidUA)
+	if agent := ua.ParseRequest(r); !ua.Equal(agent, ua.Parse(androidUA)) {
+		t.Errorf("request without hints should be parsed as user agent, got %+v", agent)
+	}
+
+	r.Header.Set("Sec-CH-UA", `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`)
+	r.Header.Set("Sec-CH-UA-Full-Version-List", `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.144", "Google Chrome";v="120.0.6099.144"`)
+	r.Header.Set("Sec-CH-UA-Mobile", "?1")
+	r.Header.Set("Sec-CH-UA-Platform", `"Android"`)
+	r.Header.Set("Sec-CH-UA-Platform-Version", `"14.0.0"`)
+	r.Header.Set("Sec-CH-UA-Model", `"Pixel 8"`)
+	agent := ua.ParseRequest(r)
+	if agent.Name != ua.Chrome || agent.Version != "120.0.6099.144" || agent.OS != ua.Android || agent.OSVersion != "14.0.0" ||
+		agent.Device != "Pixel 8" || agent.DeviceType() != ua.DevicePhone {
+		t.Errorf("hints should be merged, got %s %s %s %s %q %s", agent.Name, agent.Version, agent.OS, agent.OSVersion, agent.Device, agent.DeviceType())
+	}
+}
+
+func TestMergeHintsDesktopMode(t *testing.T) {
+	const linuxChrome = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	tests := []struct {
+		hints       ua.ClientHints
+		os          string
+		deviceType  ua.DeviceType
+		desktopMode bool
+	}{
+		{ua.ClientHints{Mobile: "?1", Platform: `"Android"`, PlatformVersion: `"14.0.0"`}, ua.Android, ua.DevicePhone, true},
+		{ua.ClientHints{Mobile: "?0", Platform: `"Android"`}, ua.Android, ua.DeviceUnknown, true},
+		{ua.ClientHints{Mobile: "?0", Platform: `"Android"`, Model: `"SM-X710"`}, ua.Android, ua.DeviceTablet, true},
+		{ua.ClientHints{Mobile: "?0", Platform: `"Android"`, Model: `"Pixel 8"`}, ua.Android, ua.DevicePhone, true},
+		{ua.ClientHints{Mobile: "?0", Platform: `"Linux"`}, ua.Linux, ua.DeviceDesktop, false},
+		{ua.ClientHints{}, ua.Linux, ua.DeviceDesktop, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(linuxChrome)
+		agent.MergeHints(test.hints)
+		if agent.OS != test.os || agent.DeviceType() != test.deviceType || agent.DesktopMode != test.desktopMode {
+			t.Errorf("%+v\nshould be %s %s DesktopMode=%v not %s %s %v", test.hints, test.os, test.deviceType, test.desktopMode, agent.OS, agent.DeviceType(), agent.DesktopMode)
+		}
+	}
+
+	// Android user agent is not desktop mode
+	agent := ua.Parse(androidUA)
+	agent.MergeHints(ua.ClientHints{Mobile: "?1", Platform: `"Android"`})
+	if agent.DesktopMode {
+		t.Error("Android user agent should not be desktop mode")
+	}
+}
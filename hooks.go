@@ -0,0 +1,50 @@
+package useragent
+
+import "time"
+
+// Hooks are instrumentation callbacks called after each parse, so parser
+// health metrics can be exported without wrapping every call. Nil callbacks
+// are skipped. Callbacks are called synchronously and must be safe for
+// concurrent use when the parser is.
+type Hooks struct {
+	// OnParse is called with parse result and parsing duration
+	OnParse func(ua UserAgent, d time.Duration)
+
+	// OnFallback is called when user agent is not recognized and the name
+	// is picked from the remaining tokens
+	OnFallback func(ua UserAgent)
+
+	// OnBot is called when user agent is detected as bot
+	OnBot func(ua UserAgent)
+}
+
+// SetHooks sets instrumentation callbacks of the parser. Parsing has no
+// overhead when no hooks are set, SetHooks(Hooks{}) removes them.
+func (p *Parser) SetHooks(h Hooks) {
+	if h.OnParse == nil && h.OnFallback == nil && h.OnBot == nil {
+		p.hooks = nil
+		return
+	}
+	p.hooks = &h
+}
+
+// parseWithHooks parses user agent and calls the hooks, reporting whether
+// the name is picked by fallback
+func (p *Parser) parseWithHooks(userAgent string) (UserAgent, bool) {
+	h := p.hooks
+	var start time.Time
+	if h.OnParse != nil {
+		start = time.Now()
+	}
+	ua, fallback := p.detect(userAgent)
+	if h.OnParse != nil {
+		h.OnParse(ua, time.Since(start))
+	}
+	if fallback && h.OnFallback != nil {
+		h.OnFallback(ua)
+	}
+	if ua.Bot && h.OnBot != nil {
+		h.OnBot(ua)
+	}
+	return ua, fallback
+}
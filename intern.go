@@ -0,0 +1,39 @@
+package useragent
+
+import "strconv"
+
+// interned are token keys and values sent by most browsers, parse results
+// reference these shared strings instead of allocating new ones
+var interned = func() map[string]string {
+	m := make(map[string]string)
+	for _, s := range []string{
+		Mozilla, "5.0", "AppleWebKit", "537.36", "605.1.15", "604.1", "KHTML, like Gecko", "Gecko", "20100101",
+		Chrome, Safari, Mobile, MobileSafari, Version, Firefox, "Edg", "EdgA", "EdgiOS", "OPR", "SamsungBrowser",
+		"CriOS", "FxiOS", "15E148", "4.0", "10.0", "compatible", "U", "K", "wv",
+		"Windows NT 10.0", "Windows NT 6.1", "Win64", "x64", "WOW64", "X11", "Linux", "Linux x86_64", "Ubuntu",
+		"Macintosh", "Intel Mac OS X 10_15_7", "iPhone", "iPad", "CPU iPhone OS 17_0 like Mac OS X",
+		"en-US", "en-us", "en-GB",
+	} {
+		m[s] = s
+	}
+	// reduced Android and Chrome versions, like "Android 10" and "120.0.0.0"
+	for v := 10; v <= 16; v++ {
+		s := "Android " + strconv.Itoa(v)
+		m[s] = s
+	}
+	for v := 100; v <= 150; v++ {
+		s := strconv.Itoa(v) + ".0.0.0"
+		m[s] = s
+	}
+	return m
+}()
+
+// intern returns shared string equal to b if there is one,
+// otherwise b is converted to new string
+func intern(b []byte) string {
+	// map lookup by converted byte slice doesn't allocate
+	if s, ok := interned[string(b)]; ok {
+		return s
+	}
+	return string(b)
+}
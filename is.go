@@ -55,6 +55,16 @@ func (ua UserAgent) IsFirefox() bool {
 	return ua.Name == Firefox
 }
 
+// IsGeckoFamily returns true for Firefox and Firefox based browsers,
+// like Firefox Focus, Waterfox, LibreWolf, Pale Moon, SeaMonkey and IceCat
+func (ua UserAgent) IsGeckoFamily() bool {
+	switch ua.Name {
+	case Firefox, FirefoxFocus, FirefoxKlar, Waterfox, LibreWolf, PaleMoon, SeaMonkey, IceCat:
+		return true
+	}
+	return false
+}
+
 // IsInternetExplorer shorthand function to check if Name == Internet Explorer
 func (ua UserAgent) IsInternetExplorer() bool {
 	return ua.Name == InternetExplorer
@@ -96,7 +106,9 @@ func (ua UserAgent) IsYandexbot() bool {
 }
 
 // IsUnknown returns true if the package can't determine the user agent reliably.
-// Fields like Name, OS, etc. might still have values.
+// Fields like Name, OS, etc. might still have values. Empty and whitespace only
+// user agents, and "-" placeholder of access logs, are parsed as zero value
+// UserAgent with only Raw set, which is unknown.
 func (ua UserAgent) IsUnknown() bool {
-	return !ua.Mobile && !ua.Tablet && !ua.Desktop && !ua.Bot
+	return ua.DeviceType() == DeviceUnknown
 }
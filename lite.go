@@ -0,0 +1,48 @@
+package useragent
+
+// Lite is compact parse result for pipelines keeping millions of parsed
+// user agents in memory. It is 56 bytes on 64-bit platforms, compared to
+// several hundred bytes of UserAgent, and it doesn't keep the raw user agent
+// string. Builtin names are shared constants and common versions, like
+// reduced Chrome versions, are shared strings as well. Name of unrecognized
+// user agent, which is the raw user agent, and names and versions found by
+// custom rules and matchers, which may be substrings of it, are copied.
+type Lite struct {
+	Name    string
+	Version string
+	OS      string
+	Mobile  bool
+	Bot     bool
+}
+
+// ParseLite parses user agent string returning only the name, version, OS,
+// mobile and bot flags
+func ParseLite(userAgent string) Lite {
+	return defaultParser().ParseLite(userAgent)
+}
+
+// ParseLite parses user agent string returning only the name, version, OS,
+// mobile and bot flags
+func (p *Parser) ParseLite(userAgent string) Lite {
+	ua, fallback := p.parseFallback(userAgent)
+	l := Lite{
+		Name:    ua.Name,
+		Version: ua.Version,
+		OS:      ua.OS,
+		Mobile:  ua.Mobile,
+		Bot:     ua.Bot,
+	}
+	if fallback || len(p.rules) != 0 || len(p.matchers) != 0 {
+		l.Name, l.Version = cloneString(l.Name), cloneString(l.Version)
+	}
+	return l
+}
+
+// cloneString returns copy of s, so substrings of the user agent don't keep
+// the whole user agent in memory
+func cloneString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return string([]byte(s))
+}
@@ -0,0 +1,66 @@
+package useragent
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Matcher sets user agent fields from the regular expression match,
+// match[0] is the matched text and the rest are the submatches
+type Matcher func(ua *UserAgent, match []string)
+
+// matcher is a registered regular expression matcher
+type matcher struct {
+	priority int
+	re       *regexp.Regexp
+	fn       Matcher
+}
+
+// compileRegexp compiles pattern or returns the one already compiled by the
+// parser, so matchers and rules with the same pattern share it. Compiled
+// patterns are released with the parser.
+func (p *Parser) compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := p.regexps[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if p.regexps == nil {
+		p.regexps = make(map[string]*regexp.Regexp)
+	}
+	p.regexps[pattern] = re
+	return re, nil
+}
+
+// AddMatcher registers regular expression matcher for user agents which can't
+// be detected by tokens, like "AcmeOS-3.2 (Linux; U; Android 11; ...)".
+// Matchers are checked only when neither the builtin detection nor the loaded
+// rules recognize the user agent, so they are off the hot path. Matchers with
+// higher priority are checked first, and only the first matching one is applied.
+// Compiled patterns are kept by the parser and shared with its rules.
+func (p *Parser) AddMatcher(priority int, pattern string, m Matcher) error {
+	re, err := p.compileRegexp(pattern)
+	if err != nil {
+		return err
+	}
+	p.matchers = append(p.matchers, matcher{priority: priority, re: re, fn: m})
+	sort.SliceStable(p.matchers, func(i, j int) bool {
+		return p.matchers[i].priority > p.matchers[j].priority
+	})
+	return nil
+}
+
+// applyMatchers applies the first matching regexp matcher, returns index of
+// the matcher or -1 if none match. User agent is passed
+// by value, so it doesn't escape to heap in Parse when no matchers are set.
+func (p *Parser) applyMatchers(ua UserAgent) (UserAgent, int) {
+	for i, m := range p.matchers {
+		if match := m.re.FindStringSubmatch(ua.Raw); match != nil {
+			m.fn(&ua, match)
+			return ua, i
+		}
+	}
+	return ua, -1
+}
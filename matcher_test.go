@@ -0,0 +1,53 @@
+package useragent_test
+
+import (
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestAddMatcher(t *testing.T) {
+	p := ua.NewParser()
+	err := p.AddMatcher(0, `^AcmeOS-([\d.]+) \(Linux; U; Android ([\d.]+)`, func(agent *ua.UserAgent, m []string) {
+		agent.Name = "AcmeOS"
+		agent.Version = m[1]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// higher priority matcher is checked first
+	err = p.AddMatcher(10, `^AcmeOS-([\d.]+) \(Linux; U; Android [\d.]+; AcmePhone`, func(agent *ua.UserAgent, m []string) {
+		agent.Name = "Acme App"
+		agent.Version = m[1]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// not checked for recognized user agents
+	err = p.AddMatcher(0, `Chrome`, func(agent *ua.UserAgent, m []string) {
+		agent.Name = "Matched"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"AcmeOS-3.2 (Linux; U; Android 11; SM-A515F Build/RP1A.200720.012)", "AcmeOS", "3.2"},
+		{"AcmeOS-3.2 (Linux; U; Android 11; AcmePhone Build/RP1A.200720.012)", "Acme App", "3.2"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0"},
+	}
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.version, "not", agent.Name, agent.Version)
+		}
+	}
+
+	if err := p.AddMatcher(0, `(`, func(*ua.UserAgent, []string) {}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
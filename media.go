@@ -0,0 +1,12 @@
+package useragent
+
+// mediaPlayers are media players and playback libraries of streaming
+// clients, grouped by the first letter of the hint
+var mediaPlayers = [256][]rawClient{
+	'v': {{"vlc/", "VLC", "VLC"}},
+	'l': {{"lavf", "Lavf", "Lavf"}},
+	'g': {{"gstreamer", "GStreamer", "GStreamer"}},
+	'e': {{"exoplayer", "ExoPlayer", "ExoPlayerLib"}},
+	'a': {{"applecoremedia", "AppleCoreMedia", "AppleCoreMedia"}, {"androidxmedia3", "ExoPlayer", "AndroidXMedia3"}},
+	's': {{"stagefright", "stagefright", "stagefright"}},
+}
@@ -0,0 +1,42 @@
+package useragent
+
+import "sort"
+
+// Dumps, like DumpRules, are compared by golden tests and diff based tools,
+// so they must be the same in every run. Lists with meaningful order, like
+// rules, matchers and precedence, keep their order. Sets and tables stored
+// in maps are put in canonical order by the functions below, never written
+// in map iteration order, and unordered builtin lists are sorted as well.
+
+// sortedSet returns members of the set in ascending order
+func sortedSet(set map[string]bool) []string {
+	s := make([]string, 0, len(set))
+	for k := range set {
+		s = append(s, k)
+	}
+	sort.Strings(s)
+	return s
+}
+
+// sortedStrings returns sorted copy of the list, the list is not modified
+func sortedStrings(list []string) []string {
+	s := append(make([]string, 0, len(list)), list...)
+	sort.Strings(s)
+	return s
+}
+
+// aliasDump is name alias set with SetNameAlias
+type aliasDump struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// sortedAliases returns aliases ordered by the builtin name
+func sortedAliases(aliases map[string]string) []aliasDump {
+	d := make([]aliasDump, 0, len(aliases))
+	for from, to := range aliases {
+		d = append(d, aliasDump{From: from, To: to})
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].From < d[j].From })
+	return d
+}
@@ -0,0 +1,229 @@
+package useragent
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// Parser parses user agent strings with custom ignore list and token filters.
+// Configure the Parser before use, it is safe for concurrent use only when
+// it is not modified.
+type Parser struct {
+	ignore    map[string]bool
+	filters   []TokenFilter
+	fallback  Fallback
+	aliases   map[string]string
+	extended  bool
+	prec      []string
+	tablets   []string
+	hooks     *Hooks
+	debug     bool
+	rawTokens bool
+	rules     []Rule
+	limit     int // 0 is DefaultParseLimit, negative disables the limit
+	matchers  []matcher
+	regexps   map[string]*regexp.Regexp // compiled patterns of rules and matchers
+	buffers   atomic.Value              // *sync.Pool of *tokenBuffers
+
+	devices   *DeviceDB // nil is the builtin database, unless noDevices is set
+	noDevices bool
+}
+
+// maxBufferCap is the largest tokenizer buffer capacity kept for reuse,
+// buffers grown by longer tokens are released
+const maxBufferCap = 1024
+
+// tokenBuffers are reusable tokenizer buffers for token key and value
+type tokenBuffers struct {
+	buff bytes.Buffer
+	val  bytes.Buffer
+}
+
+// TokenFilter rewrites token key and value before detection.
+// Returning empty key removes the token.
+type TokenFilter func(key, value string) (string, string)
+
+// builtinParser is used by package level functions unless replaced by SetDefault
+var builtinParser = NewParser()
+
+// customParser holds *Parser set by SetDefault
+var customParser atomic.Value
+
+// defaultParser returns parser used by package level functions
+func defaultParser() *Parser {
+	if p, ok := customParser.Load().(*Parser); ok && p != nil {
+		return p
+	}
+	return builtinParser
+}
+
+// SetDefault replaces the parser used by package level functions, like Parse,
+// ParseStrict and IsBot, so configured parser can be installed once at startup.
+// The parser must not be modified after it is set. Passing nil restores the
+// builtin parser. It is safe to call SetDefault while parsing.
+func SetDefault(p *Parser) {
+	customParser.Store(p)
+}
+
+// NewParser returns new Parser with builtin settings
+func NewParser() *Parser {
+	p := &Parser{}
+	p.buffers.Store(&sync.Pool{})
+	return p
+}
+
+// Reset releases buffers kept for reuse by the parser. Buffers are already
+// capped in size, so Reset is needed only to free memory after the peak load.
+// It is safe to call Reset while parsing.
+func (p *Parser) Reset() {
+	p.buffers.Store(&sync.Pool{})
+}
+
+// getBuffers returns buffers kept for reuse or new ones. Zero value Parser,
+// not created by NewParser, has no pool and allocates buffers for every parse.
+func (p *Parser) getBuffers() *tokenBuffers {
+	if pool, ok := p.buffers.Load().(*sync.Pool); ok {
+		if b, ok := pool.Get().(*tokenBuffers); ok {
+			b.buff.Reset()
+			b.val.Reset()
+			return b
+		}
+	}
+	b := &tokenBuffers{}
+	b.buff.Grow(32)
+	b.val.Grow(32)
+	return b
+}
+
+// putBuffers returns buffers for reuse, unless they grew over maxBufferCap
+// or the parser has no pool
+func (p *Parser) putBuffers(b *tokenBuffers) {
+	if b.buff.Cap() > maxBufferCap || b.val.Cap() > maxBufferCap {
+		return
+	}
+	if pool, ok := p.buffers.Load().(*sync.Pool); ok {
+		pool.Put(b)
+	}
+}
+
+// DefaultParseLimit is the default length of user agent prefix parsed by the parser
+const DefaultParseLimit = 2048
+
+// SetParseLimit sets the longest user agent prefix which is parsed, default
+// is DefaultParseLimit. Some SDKs send multi-kilobyte user agents with long
+// blobs appended, they are cut at the last space before the limit and only the
+// prefix is parsed, so detection still works. Truncated is set on the result
+// and Raw keeps the whole user agent. Zero or negative limit disables truncation.
+func (p *Parser) SetParseLimit(n int) {
+	if n <= 0 {
+		n = -1
+	}
+	p.limit = n
+}
+
+func (p *Parser) parseLimit() int {
+	if p.limit == 0 {
+		return DefaultParseLimit
+	}
+	return p.limit
+}
+
+// Ignore adds tokens that will be skipped by the parser, like builtin
+// "KHTML, like Gecko" or "compatible" tokens
+func (p *Parser) Ignore(tokens ...string) {
+	if p.ignore == nil {
+		p.ignore = make(map[string]bool, len(tokens))
+	}
+	for _, t := range tokens {
+		p.ignore[t] = true
+	}
+}
+
+// AddFilter registers token filter. Filters are applied in the order
+// they were added.
+func (p *Parser) AddFilter(f TokenFilter) {
+	p.filters = append(p.filters, f)
+}
+
+// Fallback configures how the name is picked from the remaining tokens when
+// the user agent is not recognized. OS, engine and platform tokens like
+// Mozilla, AppleWebKit, Chrome, Safari, Version, Windows NT, Android or Linux
+// are never picked. The zero value is the default behavior: the first token
+// with a version is picked, or the first token without a version if none has it.
+type Fallback struct {
+	// Exclude tokens from being picked as name
+	Exclude []string
+
+	// RequireVersion picks only tokens with a version
+	RequireVersion bool
+
+	// Last picks the last matching token instead of the first one
+	Last bool
+}
+
+func (f *Fallback) excluded(key string) bool {
+	for _, e := range f.Exclude {
+		if e == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFallback sets the fallback strategy of the parser
+func (p *Parser) SetFallback(f Fallback) {
+	p.fallback = f
+}
+
+// SetNameAlias reports browser name from as to, e.g. SetNameAlias(Edge, "MS Edge").
+// Is* methods compare builtin names, so they don't match aliased names.
+func (p *Parser) SetNameAlias(from, to string) {
+	if p.aliases == nil {
+		p.aliases = make(map[string]string)
+	}
+	p.aliases[from] = to
+}
+
+// defaultPrecedence is builtin order of browser tokens
+var defaultPrecedence = []string{"EdgiOS", "EdgA", "Edg", Edge, "OPR", "OPX", "OPT", "OPiOS",
+	"SamsungBrowser", "HuaweiBrowser", Vivaldi, "Silk", "CriOS", "FxiOS", Firefox}
+
+// DefaultPrecedence returns builtin order of browser tokens, used when more
+// than one of them is sent, e.g. Edge on Android sends both EdgA and Chrome.
+func DefaultPrecedence() []string {
+	return append([]string(nil), defaultPrecedence...)
+}
+
+// SetPrecedence sets the order of browser tokens, first token found with a
+// version wins. Tokens without builtin detection are reported as name and
+// version, like YaBrowser/23.1 as YaBrowser 23.1. Chrome and Safari are
+// implicitly last unless listed, so vendor tokens sent along with them win.
+// Bots and in-app browsers are detected before tokens without builtin detection.
+func (p *Parser) SetPrecedence(tokens ...string) {
+	p.prec = append([]string(nil), tokens...)
+}
+
+// precedence returns browser tokens order of the parser
+func (p *Parser) precedence() []string {
+	if p.prec == nil {
+		return defaultPrecedence
+	}
+	return p.prec
+}
+
+func (p *Parser) ignored(s string) bool {
+	return ignore(s) || p.ignore[s]
+}
+
+// filter applies token filters, returns false if token is removed
+func (p *Parser) filter(prop property) (property, bool) {
+	for _, f := range p.filters {
+		prop.Key, prop.Value = f(prop.Key, prop.Value)
+		if prop.Key == "" {
+			return prop, false
+		}
+	}
+	return prop, true
+}
@@ -0,0 +1,400 @@
+package useragent_test
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ua "github.com/mileusna/useragent"
+)
+
+const proxyUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 MyCorp-Proxy/2.1"
+
+func TestParserIgnore(t *testing.T) {
+	if name := ua.Parse(proxyUA).Name; name != "MyCorp-Proxy" {
+		t.Fatal("expected proxy token to be picked by default parser, got", name)
+	}
+
+	p := ua.NewParser()
+	p.Ignore("MyCorp-Proxy")
+	agent := p.Parse(proxyUA)
+	if agent.Name != ua.Chrome || agent.Version != "120.0.0.0" {
+		t.Error("\nName should be Chrome 120.0.0.0 not", agent.Name, agent.Version)
+	}
+
+	// package level parser is not affected
+	if name := ua.Parse(proxyUA).Name; name != "MyCorp-Proxy" {
+		t.Error("custom ignore list leaked into default parser, got", name)
+	}
+}
+
+func TestParserFilter(t *testing.T) {
+	p := ua.NewParser()
+	p.AddFilter(func(key, value string) (string, string) {
+		if strings.HasPrefix(key, "MyCorp-") {
+			return "", ""
+		}
+		return key, value
+	})
+	p.AddFilter(func(key, value string) (string, string) {
+		if key == "Chrome" {
+			return key, strings.TrimSuffix(value, ".0.0.0")
+		}
+		return key, value
+	})
+	agent := p.Parse(proxyUA)
+	if agent.Name != ua.Chrome || agent.Version != "120" {
+		t.Error("\nName should be Chrome 120 not", agent.Name, agent.Version)
+	}
+}
+
+func TestParserFallback(t *testing.T) {
+	const s = "AcmePanel/1.0 (Linux; armv7l) AcmeBrowser/2.3 KioskMode"
+	tests := []struct {
+		fallback ua.Fallback
+		name     string
+		version  string
+	}{
+		{ua.Fallback{}, "AcmePanel", "1.0"},
+		{ua.Fallback{Last: true}, "AcmeBrowser", "2.3"},
+		{ua.Fallback{Exclude: []string{"AcmePanel"}}, "AcmeBrowser", "2.3"},
+		{ua.Fallback{Exclude: []string{"AcmePanel", "AcmeBrowser"}}, "armv7l", ""},
+		{ua.Fallback{Exclude: []string{"AcmePanel", "AcmeBrowser"}, RequireVersion: true}, s, ""},
+	}
+	for _, test := range tests {
+		p := ua.NewParser()
+		p.SetFallback(test.fallback)
+		agent := p.Parse(s)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Errorf("%+v\nName should be %q %q not %q %q", test.fallback, test.name, test.version, agent.Name, agent.Version)
+		}
+	}
+}
+
+func TestParserNameAlias(t *testing.T) {
+	p := ua.NewParser()
+	p.SetNameAlias(ua.Edge, "MS Edge")
+	p.SetNameAlias("Go-http-client", "Go")
+
+	tests := []struct {
+		ua   string
+		name string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", "MS Edge"},
+		{"Go-http-client/1.1", "Go"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome},
+	}
+	for _, test := range tests {
+		if name := p.Parse(test.ua).Name; name != test.name {
+			t.Error("\n", test.ua, "\nName should be", test.name, "not", name)
+		}
+	}
+}
+
+func TestParserPrecedence(t *testing.T) {
+	const (
+		edgeOpera = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 OPR/79.0.4195.76660 EdgA/120.0.2210.115"
+		samsung   = "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36"
+		yandex    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 YaBrowser/24.1.0.0 Vivaldi/6.5.3206.53 Safari/537.36"
+	)
+	tests := []struct {
+		precedence []string
+		ua         string
+		name       string
+		version    string
+	}{
+		{nil, edgeOpera, ua.Edge, "120.0.2210.115"},
+		{[]string{"OPR", "EdgA"}, edgeOpera, ua.Opera, "79.0.4195.76660"},
+		{nil, samsung, ua.SamsungBrowser, "23.0"},
+		{[]string{ua.Chrome, "SamsungBrowser"}, samsung, ua.Chrome, "115.0.0.0"},
+		{nil, yandex, ua.Vivaldi, "6.5.3206.53"},
+		{append([]string{"YaBrowser"}, ua.DefaultPrecedence()...), yandex, "YaBrowser", "24.1.0.0"},
+	}
+	for _, test := range tests {
+		p := ua.NewParser()
+		if test.precedence != nil {
+			p.SetPrecedence(test.precedence...)
+		}
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Error("\n", test.precedence, test.ua, "\nshould be", test.name, test.version, "not", agent.Name, agent.Version)
+		}
+	}
+
+	// returned precedence is a copy
+	ua.DefaultPrecedence()[0] = "OPR"
+	if agent := ua.Parse(edgeOpera); agent.Name != ua.Edge {
+		t.Error("DefaultPrecedence modified builtin precedence, got", agent.Name)
+	}
+}
+
+func TestParserHooks(t *testing.T) {
+	var parsed, fallbacks, bots int
+	p := ua.NewParser()
+	p.SetHooks(ua.Hooks{
+		OnParse: func(agent ua.UserAgent, d time.Duration) {
+			if agent.Raw == "" || d < 0 {
+				t.Error("OnParse called with", agent.Raw, d)
+			}
+			parsed++
+		},
+		OnFallback: func(ua.UserAgent) { fallbacks++ },
+		OnBot:      func(ua.UserAgent) { bots++ },
+	})
+	for _, s := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"AcmePanel/1.0 (Linux; armv7l)",
+	} {
+		p.Parse(s)
+	}
+	if parsed != 3 || fallbacks != 1 || bots != 1 {
+		t.Error("hooks called", parsed, fallbacks, bots, "times, should be 3 1 1")
+	}
+
+	p.SetHooks(ua.Hooks{})
+	p.Parse("AcmePanel/1.0 (Linux; armv7l)")
+	if parsed != 3 || fallbacks != 1 {
+		t.Error("hooks called after removal")
+	}
+}
+
+func TestParserDebugInfo(t *testing.T) {
+	if agent := ua.Parse(proxyUA); agent.Debug != nil {
+		t.Error("DebugInfo should be set only when enabled")
+	}
+
+	p := ua.NewParser()
+	p.EnableDebugInfo()
+	p.AddFilter(func(key, value string) (string, string) {
+		if key == "Win64" {
+			return "", ""
+		}
+		return key, value
+	})
+	if err := p.LoadRules(strings.NewReader(`{"rules": [{"token": "AcmeCrawler", "name": "Acme Crawler", "bot": true}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ua           string
+		stage        string
+		match        string
+		versionToken string
+	}{
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 EdgA/120.0.2210.115", ua.StageBuiltin, "EdgA", "EdgA"},
+		{proxyUA, ua.StageBuiltin, "MyCorp-Proxy", "MyCorp-Proxy"},
+		{"AcmePanel/1.0 (Linux; armv7l)", ua.StageFallback, "AcmePanel", "AcmePanel"},
+		{"AcmeCrawler/2.0", ua.StageRule, "token AcmeCrawler", "AcmeCrawler"},
+	}
+	for _, test := range tests {
+		d := p.Parse(test.ua).Debug
+		if d == nil {
+			t.Fatal("DebugInfo is not set")
+		}
+		if d.Stage != test.stage || d.Case != test.match || d.VersionToken != test.versionToken {
+			t.Error("\n", test.ua, "\nshould be", test.stage, test.match, test.versionToken, "not", d.Stage, d.Case, d.VersionToken)
+		}
+	}
+
+	d := p.Parse(proxyUA).Debug
+	if strings.Join(d.Discarded, ",") != "Mozilla,Win64,KHTML, like Gecko" {
+		t.Error("Discarded should be ignored and filtered tokens, not", d.Discarded)
+	}
+	if len(d.Tokens) == 0 || d.Tokens[len(d.Tokens)-1] != "MyCorp-Proxy/2.1" {
+		t.Error("Tokens should end with MyCorp-Proxy/2.1, not", d.Tokens)
+	}
+}
+
+func TestParserRawTokens(t *testing.T) {
+	if agent := ua.Parse(proxyUA); agent.Tokens != nil {
+		t.Error("Tokens should be set only when enabled")
+	}
+
+	p := ua.NewParser()
+	p.EnableRawTokens()
+	p.AddFilter(func(key, value string) (string, string) {
+		if key == "Win64" {
+			return "", ""
+		}
+		return key, value
+	})
+	agent := p.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 (+https://example.com/bot.html)")
+	want := []ua.Token{
+		{Key: "5.0"}, // version of the ignored Mozilla token
+		{Key: "Windows NT", Value: "10.0"},
+		{Key: "x64"},
+		{Key: "AppleWebKit", Value: "537.36"},
+		{Key: "Chrome", Value: "120.0.0.0"},
+		{Key: "Safari", Value: "537.36"},
+	}
+	if !reflect.DeepEqual(agent.Tokens, want) {
+		t.Errorf("Tokens should be\n%v\nnot\n%v", want, agent.Tokens)
+	}
+	if agent.WindowSystem != "" {
+		t.Error("WindowSystem should be empty, not", agent.WindowSystem)
+	}
+	if agent.Anonymize().Tokens != nil || !ua.Equal(agent, ua.Parse(agent.Raw)) {
+		t.Error("Tokens should be removed by Anonymize and ignored by Equal")
+	}
+}
+
+func TestParserWindowSystem(t *testing.T) {
+	const x11 = "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	if agent := ua.Parse(x11); agent.WindowSystem != "" {
+		t.Error("WindowSystem should be set only with raw tokens")
+	}
+
+	p := ua.NewParser()
+	p.EnableRawTokens()
+	for _, test := range []struct{ ua, ws string }{
+		{x11, ua.X11},
+		{"Mozilla/5.0 (Wayland; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Wayland},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.X11},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ""},
+	} {
+		if agent := p.Parse(test.ua); agent.WindowSystem != test.ws || agent.OS == "" {
+			t.Error("\n", test.ua, "\nWindowSystem should be", test.ws, "not", agent.WindowSystem, agent.OS)
+		}
+	}
+}
+
+func TestParserMemoryStable(t *testing.T) {
+	p := ua.NewParser()
+	long := strings.Repeat("x", 1<<20)
+	adversarial := long + "/" + long + " (" + long + ")"
+
+	for i := 0; i < 20; i++ {
+		testUA = p.Parse(adversarial)
+		testUA = p.Parse(proxyUA)
+	}
+	// oversized buffers must not be kept for reuse
+	if c := ua.PooledBufferCap(p); c > 1024 {
+		t.Error("parser keeps buffer with capacity", c)
+	}
+}
+
+func TestZeroValueParser(t *testing.T) {
+	var p ua.Parser
+	want := ua.Parse(proxyUA)
+	if agent := p.Parse(proxyUA); agent.Name != want.Name || agent.OS != want.OS {
+		t.Error("zero value parser should parse like the builtin one, got", agent.Name, agent.OS)
+	}
+}
+
+func TestParserReset(t *testing.T) {
+	p := ua.NewParser()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if agent := p.Parse(proxyUA); agent.OS != ua.Windows {
+					t.Error("unexpected OS", agent.OS)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		p.Reset()
+	}
+	wg.Wait()
+}
+
+func TestSetDefault(t *testing.T) {
+	p := ua.NewParser()
+	p.SetNameAlias(ua.Edge, "MS Edge")
+	ua.SetDefault(p)
+	defer ua.SetDefault(nil)
+
+	const edge = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91"
+	if name := ua.Parse(edge).Name; name != "MS Edge" {
+		t.Error("Parse should use default parser, got", name)
+	}
+	if agent, err := ua.ParseStrict(edge); err != nil || agent.Name != "MS Edge" {
+		t.Error("ParseStrict should use default parser, got", agent.Name, err)
+	}
+
+	ua.SetDefault(nil)
+	if name := ua.Parse(edge).Name; name != ua.Edge {
+		t.Error("SetDefault(nil) should restore builtin parser, got", name)
+	}
+}
+
+func TestSetDefaultIsBot(t *testing.T) {
+	p := ua.NewParser()
+	if err := p.LoadRules(strings.NewReader(`{"rules": [{"prefix": "AcmeCrawler", "name": "Acme Crawler", "bot": true}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	ua.SetDefault(p)
+	defer ua.SetDefault(nil)
+
+	if !ua.Parse("AcmeCrawler/1.0").Bot || !ua.IsBot("AcmeCrawler/1.0") {
+		t.Error("IsBot should use rules of the default parser")
+	}
+	ua.SetDefault(nil)
+	if ua.IsBot("AcmeCrawler/1.0") {
+		t.Error("SetDefault(nil) should restore builtin IsBot")
+	}
+}
+
+func TestParserTabletPattern(t *testing.T) {
+	tests := []struct {
+		ua     string
+		tablet bool
+	}{
+		{"Mozilla/5.0 (Linux; Android 11; SM-T500) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 12; SAMSUNG SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 12; Lenovo TB-X606F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 13; Redmi Pad SE) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 10; MatePad Pro Build/HUAWEIMRX-W09) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", false},
+		{"Mozilla/5.0 (Linux; Android 13; 23043RP34G) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+		{"Mozilla/5.0 (Linux; Android 14; Galaxy Tab) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 12; Tablet Phone X1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", false},
+		{"Mozilla/5.0 (Linux; Android 4.1.2; Padfone) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", false},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.Tablet != test.tablet {
+			t.Error("\n", test.ua, "\nTablet should be", test.tablet)
+		}
+	}
+
+	// Xiaomi Pad 6 model code
+	const xiaomi = "Mozilla/5.0 (Linux; Android 13; 23043RP34G) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	p := ua.NewParser()
+	p.AddTabletPattern("23043RP34")
+	if agent := p.Parse(xiaomi); !agent.Tablet || agent.Mobile {
+		t.Error("custom tablet pattern should report tablet, got", agent.DeviceType())
+	}
+}
+
+func TestParserParseLimit(t *testing.T) {
+	const chrome = "Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+	long := chrome + " " + strings.Repeat("Blob/"+strings.Repeat("x", 90)+" ", 40)
+
+	agent := ua.Parse(long)
+	if !agent.Truncated || agent.Raw != long || agent.Name != ua.Chrome || agent.Version != "120.0.0.0" || agent.Device != "SM-S911B" {
+		t.Errorf("long user agent should be truncated and detected, got Truncated=%v %s %s %s", agent.Truncated, agent.Name, agent.Version, agent.Device)
+	}
+	if agent := ua.Parse(chrome); agent.Truncated {
+		t.Error("short user agent should not be truncated")
+	}
+	// no spaces before the limit
+	if agent := ua.Parse(strings.Repeat("x", ua.DefaultParseLimit+1)); !agent.Truncated || len(agent.Name) != ua.DefaultParseLimit {
+		t.Errorf("user agent without spaces should be cut at the limit, got %d bytes name", len(agent.Name))
+	}
+
+	p := ua.NewParser()
+	p.SetParseLimit(len(chrome) + 10)
+	if agent := p.Parse(long); !agent.Truncated || agent.Name != ua.Chrome {
+		t.Errorf("user agent should be truncated by parser limit, got Truncated=%v %s", agent.Truncated, agent.Name)
+	}
+	p.SetParseLimit(0)
+	if agent := p.Parse(long); agent.Truncated || agent.Name != ua.Chrome {
+		t.Errorf("truncation should be disabled, got Truncated=%v %s", agent.Truncated, agent.Name)
+	}
+}
@@ -0,0 +1,12 @@
+package useragent
+
+// packageManagers are package managers and registry clients, grouped by
+// the first letter of the hint. pnpm and yarn send npm token as well.
+var packageManagers = [256][]rawClient{
+	'h': {{"homebrew/", "Homebrew", "Homebrew"}, {"helm/", "Helm", "Helm"}},
+	'p': {{"pip/", "pip", "pip"}, {"pnpm/", "pnpm", "pnpm"}},
+	'n': {{"npm/", "npm", "npm"}, {"nuget ", "NuGet", "NuGet"}},
+	'y': {{"yarn/", "Yarn", "yarn"}},
+	'c': {{"cargo/", "Cargo", "cargo"}, {"cargo ", "Cargo", "cargo"}, {"composer/", "Composer", "Composer"}},
+	'd': {{"docker/", "Docker", "docker"}, {"docker-client/", "Docker", "Docker-Client"}},
+}
@@ -0,0 +1,38 @@
+package useragent
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Preview and prefetch agent names
+const (
+	ChromePrefetchProxy = "Chrome Prefetch Proxy"
+	GoogleWebPreview    = "Google Web Preview"
+	BingPreview         = "Bing Preview"
+	IMessagePreview     = "iMessage Preview"
+)
+
+// prefetchAgents are tokens of page preview and prefetch agents
+var prefetchAgents = []string{"Chrome Privacy Preserving Prefetch Proxy", "Google Web Preview", "BingPreview"}
+
+var prefetchNames = map[string]string{
+	"Chrome Privacy Preserving Prefetch Proxy": ChromePrefetchProxy,
+	"Google Web Preview":                       GoogleWebPreview,
+	"BingPreview":                              BingPreview,
+}
+
+// IsPrefetchRequest returns true if request headers mark speculative prefetch
+// or preview load, like "Sec-Purpose: prefetch" sent by Chrome, "Purpose: prefetch"
+// or "X-Purpose: preview" sent by Safari and "X-Moz: prefetch" sent by Firefox.
+// Browsers send their regular user agent with these requests, so they can't be
+// recognized by Parse.
+func IsPrefetchRequest(h http.Header) bool {
+	for _, name := range []string{"Sec-Purpose", "Purpose", "X-Purpose", "X-Moz"} {
+		v := strings.ToLower(h.Get(name))
+		if strings.HasPrefix(v, "prefetch") || strings.HasPrefix(v, "preview") {
+			return true
+		}
+	}
+	return false
+}
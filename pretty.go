@@ -0,0 +1,39 @@
+package useragent
+
+import "strings"
+
+// Pretty returns compact human readable summary of the user agent,
+// like "Chrome 120.0 on Windows 10/11, desktop" or
+// "Samsung Browser 23.0 on Android 13.0 (SM-S918B), phone". UserAgent will
+// implement fmt.Stringer with the same output in v2, when deprecated String
+// field is removed.
+func (ua UserAgent) Pretty() string {
+	var sb strings.Builder
+	if ua.Name == "" {
+		sb.WriteString("Unknown")
+	} else {
+		sb.WriteString(ua.Name)
+	}
+	if v := ua.VersionNoShort(); v != "" {
+		sb.WriteString(" " + v)
+	} else if ua.Version != "" {
+		sb.WriteString(" " + ua.Version)
+	}
+
+	if os := ua.OSVersionName(); os != "" {
+		sb.WriteString(" on " + os)
+	} else if ua.OS != "" {
+		sb.WriteString(" on " + ua.OS)
+		if v := ua.OSVersionNoShort(); v != "" {
+			sb.WriteString(" " + v)
+		}
+	}
+	if ua.Device != "" {
+		sb.WriteString(" (" + ua.Device + ")")
+	}
+
+	if dt := ua.DeviceType(); dt != DeviceUnknown {
+		sb.WriteString(", " + dt.String())
+	}
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+package useragent_test
+
+import (
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestPretty(t *testing.T) {
+	tests := []struct {
+		ua     string
+		pretty string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome 120.0 on Windows 10/11, desktop"},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", "Samsung Browser 23.0 on Android 13.0 (SM-S918B), phone"},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", "Safari 10.0 on iOS 10.3 (iPad), tablet"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "Googlebot 2.1, bot"},
+		{"Wget/1.12 (linux-gnu)", "Wget 1.12"},
+		{"", "Unknown"},
+	}
+	for _, test := range tests {
+		if pretty := ua.Parse(test.ua).Pretty(); pretty != test.pretty {
+			t.Errorf("\n%s\nPretty should be %q not %q", test.ua, test.pretty, pretty)
+		}
+	}
+}
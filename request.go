@@ -0,0 +1,31 @@
+package useragent
+
+import "net/http"
+
+// ParseRequest parses User-Agent header of the request and merges
+// Sec-CH-UA client hints headers sent with it, see MergeHints
+func ParseRequest(r *http.Request) UserAgent {
+	return defaultParser().ParseRequest(r)
+}
+
+// ParseRequest parses User-Agent header of the request and merges
+// Sec-CH-UA client hints headers sent with it, see MergeHints
+func (p *Parser) ParseRequest(r *http.Request) UserAgent {
+	ua := p.Parse(r.UserAgent())
+	if h := HeaderHints(r.Header); h != (ClientHints{}) {
+		ua.MergeHints(h)
+	}
+	return ua
+}
+
+// HeaderHints returns client hints from Sec-CH-UA request headers
+func HeaderHints(h http.Header) ClientHints {
+	return ClientHints{
+		Mobile:          h.Get("Sec-CH-UA-Mobile"),
+		Platform:        h.Get("Sec-CH-UA-Platform"),
+		PlatformVersion: h.Get("Sec-CH-UA-Platform-Version"),
+		Model:           h.Get("Sec-CH-UA-Model"),
+		Brands:          h.Get("Sec-CH-UA"),
+		FullVersionList: h.Get("Sec-CH-UA-Full-Version-List"),
+	}
+}
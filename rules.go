@@ -0,0 +1,142 @@
+package useragent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// BotReasonRule is reported in BotReason when bot is detected by external rule
+const BotReasonRule = "rule"
+
+// Rule is an additional detection rule loaded with Parser.LoadRules.
+// Exactly one of Token, Prefix or Regex must be set.
+type Rule struct {
+	Token  string `json:"token,omitempty"`  // token key, like "MyBrowser" for MyBrowser/1.0
+	Prefix string `json:"prefix,omitempty"` // token key prefix
+	Regex  string `json:"regex,omitempty"`  // regular expression matched against the user agent, first group is version
+
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"` // token with the version, default is matched token or regex group
+	Bot     bool   `json:"bot,omitempty"`
+	Mobile  bool   `json:"mobile,omitempty"`
+	Tablet  bool   `json:"tablet,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// rulesFile is JSON rules file format
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules loads additional detection rules in JSON format, like
+//
+//	{"rules": [
+//		{"token": "MyCorpBrowser", "name": "MyCorp Browser"},
+//		{"prefix": "AcmeCrawler", "name": "Acme Crawler", "bot": true},
+//		{"regex": "Dalvik/[\\d.]+ \\(Linux; U; Android [^;]+; Acme", "name": "Acme App", "mobile": true}
+//	]}
+//
+// Rules are checked in the order they were loaded and the first matching rule
+// overrides the name, version and flags of the builtin detection. LoadRules can
+// be called multiple times, it returns an error without loading any of the rules
+// if any of them is invalid.
+func (p *Parser) LoadRules(r io.Reader) error {
+	var f rulesFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return fmt.Errorf("useragent: rules: %v", err)
+	}
+	for i := range f.Rules {
+		if err := f.Rules[i].compile(p); err != nil {
+			return fmt.Errorf("useragent: rule %d: %v", i+1, err)
+		}
+	}
+	p.rules = append(p.rules, f.Rules...)
+	return nil
+}
+
+// compile validates the rule and compiles its regex with the parser
+func (r *Rule) compile(p *Parser) error {
+	n := 0
+	for _, m := range []string{r.Token, r.Prefix, r.Regex} {
+		if m != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("exactly one of token, prefix or regex must be set")
+	}
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.Regex != "" {
+		re, err := p.compileRegexp(r.Regex)
+		if err != nil {
+			return err
+		}
+		r.re = re
+	}
+	return nil
+}
+
+// match returns true and version if user agent matches the rule
+func (r *Rule) match(userAgent string, tokens properties) (bool, string) {
+	var ok bool
+	var version string
+	switch {
+	case r.re != nil:
+		m := r.re.FindStringSubmatch(userAgent)
+		ok = m != nil
+		if len(m) > 1 {
+			version = m[1]
+		}
+	case r.Prefix != "":
+		for _, prop := range tokens.list {
+			if strings.HasPrefix(prop.Key, r.Prefix) {
+				ok, version = true, prop.Value
+				break
+			}
+		}
+	default:
+		for _, prop := range tokens.list {
+			if prop.Key == r.Token {
+				ok, version = true, prop.Value
+				break
+			}
+		}
+	}
+	if ok && r.Version != "" {
+		version = tokens.get(r.Version)
+	}
+	return ok, version
+}
+
+// applyRules applies the first matching external rule, returns index of
+// the rule or -1 if none match
+func (p *Parser) applyRules(ua *UserAgent, tokens properties) int {
+	for i := range p.rules {
+		r := &p.rules[i]
+		ok, version := r.match(ua.Raw, tokens)
+		if !ok {
+			continue
+		}
+		ua.Name = r.Name
+		ua.Version = version
+		if r.Bot {
+			ua.Bot = true
+			ua.BotReason = BotReasonRule
+		}
+		if r.Mobile {
+			ua.Mobile = true
+		}
+		if r.Tablet {
+			ua.Tablet = true
+		}
+		return i
+	}
+	return -1
+}
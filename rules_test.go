@@ -0,0 +1,130 @@
+package useragent_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+const testRules = `{"rules": [
+	{"token": "MyCorpBrowser", "name": "MyCorp Browser"},
+	{"prefix": "AcmeCrawler", "name": "Acme Crawler", "bot": true},
+	{"token": "KioskShell", "name": "Kiosk", "version": "Chrome", "tablet": true},
+	{"regex": "Dalvik/[\\d.]+ \\(Linux; U; Android [^;]+; AcmePhone\\).*AcmeApp v([\\d.]+)", "name": "Acme App", "mobile": true}
+]}`
+
+func TestLoadRules(t *testing.T) {
+	p := ua.NewParser()
+	if err := p.LoadRules(strings.NewReader(testRules)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ua         string
+		name       string
+		version    string
+		deviceType ua.DeviceType
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 MyCorpBrowser/3.1", "MyCorp Browser", "3.1", ua.DeviceDesktop},
+		{"AcmeCrawler-News/2.0", "Acme Crawler", "2.0", ua.DeviceBot},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36 KioskShell", "Kiosk", "118.0.0.0", ua.DeviceTablet},
+		{"Dalvik/2.1.0 (Linux; U; Android 11; AcmePhone) AcmeApp v4.2.1", "Acme App", "4.2.1", ua.DevicePhone},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0", ua.DeviceDesktop},
+	}
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.DeviceType() != test.deviceType {
+			t.Errorf("\n%s\nshould be %s %s %s, not %s %s %s", test.ua, test.name, test.version, test.deviceType, agent.Name, agent.Version, agent.DeviceType())
+		}
+	}
+}
+
+func TestLoadRulesInvalid(t *testing.T) {
+	for _, rules := range []string{
+		`{"rules": [`,
+		`{"rules": [{"name": "No match"}]}`,
+		`{"rules": [{"token": "A", "prefix": "B", "name": "Both"}]}`,
+		`{"rules": [{"token": "NoName"}]}`,
+		`{"rules": [{"regex": "(", "name": "Bad regex"}]}`,
+	} {
+		p := ua.NewParser()
+		if err := p.LoadRules(strings.NewReader(rules)); err == nil {
+			t.Error("expected error for", rules)
+		}
+	}
+}
+
+func TestDumpRules(t *testing.T) {
+	p := ua.NewParser()
+	if err := p.LoadRules(strings.NewReader(testRules)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddMatcher(1, `^AcmeOS-([\d.]+)`, func(agent *ua.UserAgent, m []string) {}); err != nil {
+		t.Fatal(err)
+	}
+	p.Ignore("Acme Toolbar", "Acme Bar", "Zeta Toolbar")
+	p.SetNameAlias(ua.Edge, "MS Edge")
+	p.SetNameAlias(ua.Chrome, "Google Chrome")
+	p.AddTabletPattern("23043RP34")
+
+	var buf, again bytes.Buffer
+	if err := p.DumpRules(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// map iteration order is random, sets and maps must be dumped sorted
+	for i := 0; i < 20; i++ {
+		again.Reset()
+		if err := p.DumpRules(&again); err != nil || buf.String() != again.String() {
+			t.Fatal("dump should be the same for the same parser", err)
+		}
+	}
+
+	var dump struct {
+		Rules    []ua.Rule `json:"rules"`
+		Matchers []struct {
+			Priority int    `json:"priority"`
+			Regex    string `json:"regex"`
+		} `json:"matchers"`
+		Ignore  []string `json:"ignore"`
+		Aliases []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"aliases"`
+		Builtin map[string][]interface{} `json:"builtin"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &dump); err != nil {
+		t.Fatal(err)
+	}
+	if len(dump.Rules) != 4 || dump.Rules[1].Prefix != "AcmeCrawler" || !dump.Rules[1].Bot {
+		t.Errorf("loaded rules should be dumped, got %+v", dump.Rules)
+	}
+	if len(dump.Matchers) != 1 || dump.Matchers[0].Priority != 1 || dump.Matchers[0].Regex != `^AcmeOS-([\d.]+)` {
+		t.Errorf("matcher should be dumped, got %+v", dump.Matchers)
+	}
+	if strings.Join(dump.Ignore, ",") != "Acme Bar,Acme Toolbar,Zeta Toolbar" {
+		t.Errorf("ignored tokens should be dumped sorted, got %v", dump.Ignore)
+	}
+	if len(dump.Aliases) != 2 || dump.Aliases[0].From != ua.Chrome || dump.Aliases[1].To != "MS Edge" {
+		t.Errorf("aliases should be dumped sorted, got %+v", dump.Aliases)
+	}
+	for _, key := range []string{"bot_keywords", "bot_domains", "scanners", "feed_readers", "media_players", "download_managers", "package_managers", "dev_tools", "webhooks", "automation_tokens", "tools", "mobile_tokens", "tablet_patterns", "case_insensitive_tokens", "ignored_tokens"} {
+		if len(dump.Builtin[key]) == 0 {
+			t.Errorf("builtin %s should be dumped", key)
+		}
+	}
+	if patterns := dump.Builtin["tablet_patterns"]; patterns[len(patterns)-1] != "23043RP34" {
+		t.Errorf("tablet pattern added to the parser should be dumped, got %v", patterns)
+	}
+
+	// dumped rules can be loaded back
+	q := ua.NewParser()
+	if err := q.LoadRules(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if agent := q.Parse("AcmeCrawler-News/2.0"); agent.Name != "Acme Crawler" || !agent.Bot {
+		t.Errorf("dumped rules should detect Acme Crawler, got %s bot=%v", agent.Name, agent.Bot)
+	}
+}
@@ -0,0 +1,13 @@
+package useragent
+
+// scanners are vulnerability scanners and internet-wide surveys,
+// grouped by the first letter of the hint
+var scanners = [256][]rawClient{
+	'n': {{"nessus", "Nessus", ""}, {"nikto", "Nikto", "Nikto"}, {"nuclei", "Nuclei", ""}},
+	's': {{"sqlmap", "sqlmap", "sqlmap"}, {"shodan", "Shodan", "Shodan"}},
+	'm': {{"masscan", "masscan", "masscan"}},
+	'z': {{"zgrab", "ZGrab", ""}},
+	'e': {{"expanse", "Expanse", ""}},
+	'c': {{"censys", "Censys", "CensysInspect"}},
+	'p': {{"paloaltonetworks", "Palo Alto Networks", ""}, {"palo alto networks", "Palo Alto Networks", ""}},
+}
@@ -0,0 +1,87 @@
+package useragent
+
+import "strings"
+
+// Screen holds screen metadata sent by in-app browsers and app SDKs.
+// Zero values mean the value is not sent.
+type Screen struct {
+	Width  int     // in pixels
+	Height int     // in pixels
+	Scale  float64 // device pixel ratio
+}
+
+// findScreen returns screen size and scale from tokens like "1170x2532",
+// "scale=3.00", "420dpi" (Instagram), "FBSS/3", "FBDM/{density=2.625,width=1080,height=2186}"
+// (Facebook) and "Scale/3.00" (AFNetworking)
+func (p properties) findScreen() Screen {
+	var s Screen
+	for _, prop := range p.list {
+		switch {
+		case prop.Key == "Scale", prop.Key == "FBSS":
+			s.Scale = parseScale(prop.Value)
+		case prop.Key == "FBDM":
+			for _, f := range strings.Split(strings.Trim(prop.Value, "{}"), ",") {
+				switch {
+				case strings.HasPrefix(f, "density="):
+					s.Scale = parseScale(f[len("density="):])
+				case strings.HasPrefix(f, "width="):
+					s.Width = parseDigits(f[len("width="):])
+				case strings.HasPrefix(f, "height="):
+					s.Height = parseDigits(f[len("height="):])
+				}
+			}
+		case prop.Value != "":
+		case strings.HasPrefix(prop.Key, "scale="):
+			s.Scale = parseScale(prop.Key[len("scale="):])
+		case strings.HasSuffix(prop.Key, "dpi"):
+			if dpi := parseDigits(prop.Key[:len(prop.Key)-len("dpi")]); dpi > 0 {
+				s.Scale = float64(dpi) / 160
+			}
+		default:
+			if i := strings.IndexByte(prop.Key, 'x'); i > 0 {
+				w, h := parseDigits(prop.Key[:i]), parseDigits(prop.Key[i+1:])
+				if w > 0 && h > 0 {
+					s.Width, s.Height = w, h
+				}
+			}
+		}
+	}
+	return s
+}
+
+// parseDigits returns integer value of s, or 0 if s is not a number
+func parseDigits(s string) int {
+	if s == "" || len(s) > 6 {
+		return 0
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
+
+// parseScale returns value of decimal number like "3" or "2.625",
+// or 0 if s is not a number
+func parseScale(s string) float64 {
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		return float64(parseDigits(s))
+	}
+	frac := s[i+1:]
+	if frac == "" {
+		return float64(parseDigits(s[:i]))
+	}
+	n, f := parseDigits(s[:i]), parseDigits(frac)
+	if n == 0 && strings.Trim(s[:i], "0") != "" || f == 0 && strings.Trim(frac, "0") != "" {
+		return 0
+	}
+	d := 1.0
+	for range frac {
+		d *= 10
+	}
+	return float64(n) + float64(f)/d
+}
@@ -0,0 +1,48 @@
+//go:build go1.21
+// +build go1.21
+
+package useragent
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, so the user agent can be logged as
+// structured groups, like slog.Any("ua", ua):
+//
+//	ua.browser.name=Chrome ua.browser.version=120.0.0.0 ua.os.name=Windows
+//	ua.os.version=10.0 ua.device.type=desktop ua.flags.mobile=false ...
+//
+// Empty fields are omitted, flags are always logged.
+func (ua UserAgent) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 4)
+	if ua.Name != "" {
+		attrs = append(attrs, slog.Group("browser", nonEmpty("name", ua.Name, "version", ua.Version)...))
+	}
+	if ua.OS != "" {
+		attrs = append(attrs, slog.Group("os", nonEmpty("name", ua.OS, "version", ua.OSVersion)...))
+	}
+	device := nonEmpty("name", ua.Device, "vendor", ua.DeviceVendor, "model", ua.DeviceModel)
+	if dt := ua.DeviceType(); dt != DeviceUnknown {
+		device = append(device, slog.String("type", dt.String()))
+	}
+	if len(device) != 0 {
+		attrs = append(attrs, slog.Group("device", device...))
+	}
+	attrs = append(attrs, slog.Group("flags",
+		slog.Bool("mobile", ua.Mobile),
+		slog.Bool("tablet", ua.Tablet),
+		slog.Bool("desktop", ua.Desktop),
+		slog.Bool("bot", ua.Bot),
+	))
+	return slog.GroupValue(attrs...)
+}
+
+// nonEmpty returns string attrs of key and value pairs with non empty value
+func nonEmpty(kv ...string) []any {
+	attrs := make([]any, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			attrs = append(attrs, slog.String(kv[i], kv[i+1]))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package useragent_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestLogValue(t *testing.T) {
+	tests := []struct {
+		ua  string
+		log string
+	}{
+		{"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			"ua.browser.name=\"Samsung Browser\" ua.browser.version=23.0 ua.os.name=Android ua.os.version=13 ua.device.name=SM-S918B ua.device.vendor=Samsung ua.device.model=\"Galaxy S23 Ultra\" ua.device.type=phone ua.flags.mobile=true ua.flags.tablet=false ua.flags.desktop=false ua.flags.bot=false"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			"ua.browser.name=Googlebot ua.browser.version=2.1 ua.device.type=bot ua.flags.mobile=false ua.flags.tablet=false ua.flags.desktop=false ua.flags.bot=true"},
+		{"", "ua.flags.mobile=false ua.flags.tablet=false ua.flags.desktop=false ua.flags.bot=false"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		logger.Info("", "ua", ua.Parse(test.ua))
+		if log := strings.TrimSpace(buf.String()); log != test.log {
+			t.Errorf("\n%s\nlog should be\n%s\nnot\n%s", test.ua, test.log, log)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package useragent
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// MaxLength is the longest user agent accepted by ParseStrict. Real user
+// agents, including long in-app browser ones, are well under this limit.
+// It is half of DefaultParseLimit, which is longer so Parse still detects
+// the prefix of multi-kilobyte SDK user agents, while ParseStrict rejects
+// them as not being user agents.
+const MaxLength = DefaultParseLimit / 2
+
+// Errors returned by ParseStrict
+var (
+	ErrEmpty       = errors.New("useragent: empty user agent")
+	ErrTooLong     = errors.New("useragent: user agent too long")
+	ErrUnparseable = errors.New("useragent: unparseable user agent")
+)
+
+// ParseStrict parses user agent like Parse, but returns an error for
+// input which is not a user agent, so it can be handled separately instead
+// of getting the raw string as Name. Parsed result is returned along with
+// ErrTooLong and ErrUnparseable. Non-fatal warnings, like truncated user agent,
+// are reported in Anomalies without an error.
+func ParseStrict(userAgent string) (UserAgent, error) {
+	return defaultParser().ParseStrict(userAgent)
+}
+
+// ParseStrict parses user agent like Parse, returning ErrEmpty, ErrTooLong
+// or ErrUnparseable for input which is not a user agent
+func (p *Parser) ParseStrict(userAgent string) (UserAgent, error) {
+	if isEmpty(userAgent) {
+		return UserAgent{Raw: userAgent, String: userAgent}, ErrEmpty
+	}
+	ua, fallback := p.parseFallback(userAgent)
+	if len(userAgent) > MaxLength {
+		return ua, ErrTooLong
+	}
+	if unparseable(&ua, fallback) {
+		return ua, ErrUnparseable
+	}
+	return ua, nil
+}
+
+// unparseable returns true if user agent is not recognized and nothing but
+// the name is picked by fallback, or user agent contains binary data
+func unparseable(ua *UserAgent, fallback bool) bool {
+	if ua.Name == "" || fallback && ua.OS == "" && ua.Version == "" && !ua.Bot {
+		return true
+	}
+	if !utf8.ValidString(ua.Raw) {
+		return true
+	}
+	for _, a := range ua.Anomalies {
+		if a == AnomalyControlChars {
+			return true
+		}
+	}
+	return false
+}
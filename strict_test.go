@@ -0,0 +1,46 @@
+package useragent_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		ua   string
+		err  error
+		name string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", nil, ua.Chrome},
+		{"curl/7.68.0", nil, "curl"},
+		{"JDownloader", nil, "JDownloader"},
+		{"Mozilla/5.0 (compatible; inoreader.com; 3 subscribers)", nil, "Inoreader"},
+		{"NewsBlur Feed Fetcher - 5 subscribers - https://www.newsblur.com/site/1234/blog", nil, "NewsBlur"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", nil, ua.Googlebot},
+		{"", ua.ErrEmpty, ""},
+		{"  ", ua.ErrEmpty, ""},
+		{"-", ua.ErrEmpty, ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 " + strings.Repeat("x", ua.MaxLength), ua.ErrTooLong, ""},
+		{"asdfghjkl", ua.ErrUnparseable, "asdfghjkl"},
+		{"\x00\x01\xff\xfe", ua.ErrUnparseable, ""},
+		{"Mozilla/5.0 (Windows NT 10.0) Chrome/120.0.0.0\r\nX-Injected: 1", ua.ErrUnparseable, ""},
+	}
+	for _, test := range tests {
+		agent, err := ua.ParseStrict(test.ua)
+		if !errors.Is(err, test.err) {
+			t.Errorf("\n%q\nerror should be %v not %v", test.ua, test.err, err)
+		}
+		if test.name != "" && agent.Name != test.name {
+			t.Errorf("\n%q\nName should be %q not %q", test.ua, test.name, agent.Name)
+		}
+	}
+
+	// warnings don't fail parsing
+	agent, err := ua.ParseStrict("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 (")
+	if err != nil || len(agent.Anomalies) == 0 {
+		t.Error("truncated user agent should be parsed with anomalies, got", err, agent.Anomalies)
+	}
+}
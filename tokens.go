@@ -0,0 +1,43 @@
+package useragent
+
+// Window systems of desktop Linux and BSD, reported in WindowSystem
+const (
+	X11     = "X11"
+	Wayland = "Wayland"
+)
+
+// Token is user agent token the parse result was derived from, like
+// Chrome/120.0.0.0 with Key "Chrome" and Value "120.0.0.0"
+type Token struct {
+	Key   string
+	Value string
+}
+
+// EnableRawTokens keeps the tokens the user agent was derived from in
+// UserAgent.Tokens, for debugging, replays and custom post-processing.
+// Tokens are reported after ignored tokens are skipped and token filters
+// are applied, URLs and locale are reported in their own fields. The token
+// list is allocated only when enabled, so it is disabled by default.
+// Window system token, X11 or Wayland, is reported in WindowSystem as well.
+func (p *Parser) EnableRawTokens() {
+	p.rawTokens = true
+}
+
+// findWindowSystem returns X11 or Wayland window system token
+func (p properties) findWindowSystem() string {
+	for _, prop := range p.list {
+		if prop.Key == X11 || prop.Key == Wayland {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// rawTokens returns copy of the tokens used for detection
+func (p properties) rawTokens() []Token {
+	tokens := make([]Token, len(p.list))
+	for i, prop := range p.list {
+		tokens[i] = Token{Key: prop.Key, Value: prop.Value}
+	}
+	return tokens
+}
@@ -0,0 +1,16 @@
+package useragent
+
+// toolNames are names of HTTP client libraries, SDKs and command line tools
+var toolNames = []string{"curl", "Wget", "Go-http-client", "python-requests", "python-urllib3", "Python-urllib",
+	"Apache-HttpClient", "Java", "PostmanRuntime", "axios", "node-fetch", "undici", "libwww-perl"}
+
+// isTool returns true for names of HTTP client libraries, SDKs and
+// command line tools
+func isTool(name string) bool {
+	for _, t := range toolNames {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+// Command compat compares the package results with the ua-parser/uap-core
+// test fixtures and reports agreement per field.
+//
+// Usage:
+//
+//	go run ./tools/compat -ua uap-core/tests/test_ua.yaml -os uap-core/tests/test_os.yaml -device uap-core/tests/test_device.yaml
+//
+// Use -diff to print every disagreement.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mileusna/useragent"
+)
+
+// uap-core family names which differ from the package names
+var browserFamilies = map[string]string{
+	"Chrome Mobile":              useragent.Chrome,
+	"Chrome Mobile iOS":          useragent.Chrome,
+	"Chrome Mobile WebView":      useragent.Chrome,
+	"HeadlessChrome":             useragent.HeadlessChrome,
+	"Firefox Mobile":             useragent.Firefox,
+	"Firefox iOS":                useragent.Firefox,
+	"Mobile Safari":              useragent.Safari,
+	"Mobile Safari UI/WKWebView": useragent.Safari,
+	"Edge Mobile":                useragent.Edge,
+	"IE":                         useragent.InternetExplorer,
+	"IE Mobile":                  useragent.InternetExplorer,
+	"Opera Mobile":               useragent.Opera,
+	"Samsung Internet":           useragent.SamsungBrowser,
+	"Amazon Silk":                useragent.Silk,
+	"Facebook":                   useragent.FacebookApp,
+	"Instagram":                  useragent.InstagramApp,
+	"YandexBot":                  useragent.YandexBot,
+	"bingbot":                    useragent.Bingbot,
+}
+
+var osFamilies = map[string]string{
+	"Mac OS X":      useragent.MacOS,
+	"Chrome OS":     useragent.ChromeOS,
+	"Windows Phone": useragent.WindowsPhone,
+	"BlackBerry OS": useragent.BlackBerry,
+	"Ubuntu":        useragent.Linux,
+	"Fedora":        useragent.Linux,
+	"Debian":        useragent.Linux,
+	"Tizen":         useragent.Tizen,
+	"KaiOS":         useragent.KaiOS,
+}
+
+// stats of a single compared field
+type stats struct {
+	total, agree int
+	diffs        []string
+}
+
+func (s *stats) add(ua, want, got string) {
+	s.total++
+	if want == got {
+		s.agree++
+		return
+	}
+	s.diffs = append(s.diffs, fmt.Sprintf("want %q got %q\n\t%s", want, got, ua))
+}
+
+func main() {
+	uaFile := flag.String("ua", "", "uap-core test_ua.yaml")
+	osFile := flag.String("os", "", "uap-core test_os.yaml")
+	deviceFile := flag.String("device", "", "uap-core test_device.yaml")
+	diff := flag.Bool("diff", false, "print disagreements")
+	flag.Parse()
+
+	if *uaFile == "" && *osFile == "" && *deviceFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	results := map[string]*stats{}
+	field := func(name string) *stats {
+		if results[name] == nil {
+			results[name] = &stats{}
+		}
+		return results[name]
+	}
+
+	if *uaFile != "" {
+		for _, c := range mustRead(*uaFile) {
+			ua := useragent.Parse(c["user_agent_string"])
+			field("browser family").add(ua.Raw, mapFamily(browserFamilies, c["family"]), ua.Name)
+			if c["major"] != "" {
+				field("browser major").add(ua.Raw, c["major"], fmt.Sprint(ua.VersionNo.Major))
+			}
+		}
+	}
+
+	if *osFile != "" {
+		for _, c := range mustRead(*osFile) {
+			ua := useragent.Parse(c["user_agent_string"])
+			field("os family").add(ua.Raw, mapFamily(osFamilies, c["family"]), ua.OS)
+			if c["major"] != "" && ua.OS != useragent.Windows { // Windows uses NT versions
+				field("os major").add(ua.Raw, c["major"], fmt.Sprint(ua.OSVersionNo.Major))
+			}
+		}
+	}
+
+	if *deviceFile != "" {
+		for _, c := range mustRead(*deviceFile) {
+			if c["model"] == "" {
+				continue
+			}
+			ua := useragent.Parse(c["user_agent_string"])
+			field("device model").add(ua.Raw, c["model"], ua.Device)
+		}
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := results[name]
+		fmt.Printf("%-16s %6d/%-6d %6.2f%%\n", name, s.agree, s.total, 100*float64(s.agree)/float64(s.total))
+	}
+	if *diff {
+		for _, name := range names {
+			for _, d := range results[name].diffs {
+				fmt.Printf("%s: %s\n", name, d)
+			}
+		}
+	}
+}
+
+func mapFamily(families map[string]string, family string) string {
+	if f, ok := families[family]; ok {
+		return f
+	}
+	if family == "Other" {
+		return ""
+	}
+	return family
+}
+
+func mustRead(filename string) []map[string]string {
+	f, err := os.Open(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	cases, err := readFixtures(f)
+	if err != nil {
+		log.Fatal(filename, ": ", err)
+	}
+	return cases
+}
+
+// unquote removes YAML single or double quotes from the scalar value
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "~", s == "null":
+		return ""
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1)
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		return strings.Replace(s[1:len(s)-1], `\"`, `"`, -1)
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readFixtures reads uap-core test fixtures. Only the subset of YAML used by
+// the fixtures is supported, a list of flat maps with scalar values:
+//
+//	test_cases:
+//	  - user_agent_string: 'Mozilla/5.0 ...'
+//	    family: 'Firefox'
+//	    major: '3'
+func readFixtures(r io.Reader) ([]map[string]string, error) {
+	var cases []map[string]string
+	var current map[string]string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || s[0] == '#' || s == "test_cases:" {
+			continue
+		}
+		if strings.HasPrefix(s, "- ") {
+			current = map[string]string{}
+			cases = append(cases, current)
+			s = strings.TrimSpace(s[2:])
+		}
+		i := strings.Index(s, ":")
+		if i == -1 || current == nil {
+			return cases, fmt.Errorf("line %d: unexpected %q", line, s)
+		}
+		current[strings.TrimSpace(s[:i])] = unquote(s[i+1:])
+	}
+	return cases, sc.Err()
+}
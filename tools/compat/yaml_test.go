@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadFixtures(t *testing.T) {
+	const fixtures = `test_cases:
+
+  - user_agent_string: 'Mozilla/5.0 (Windows; U; Windows NT 5.1; en-US) Gecko Firefox/3.5.1'
+    family: 'Firefox'
+    major: '3'
+    minor: '5'
+    patch:
+
+  - user_agent_string: "Mozilla/5.0 (compatible; it''s a bot)"
+    family: 'It''s'
+    major: ~
+`
+	cases, err := readFixtures(strings.NewReader(fixtures))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 {
+		t.Fatal("expected 2 cases, got", len(cases))
+	}
+	if cases[0]["family"] != "Firefox" || cases[0]["major"] != "3" || cases[0]["patch"] != "" {
+		t.Error("unexpected first case", cases[0])
+	}
+	if cases[1]["family"] != "It's" || cases[1]["major"] != "" {
+		t.Error("unexpected second case", cases[1])
+	}
+}
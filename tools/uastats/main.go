@@ -0,0 +1,182 @@
+// Command uastats parses user agent log, one user agent per line, and reports
+// top browsers, operating systems and device types, the rate of unknown user
+// agents and the most frequent user agents which are not recognized, so only
+// the raw string is reported as name.
+//
+// Usage:
+//
+//	go run ./tools/uastats -top 20 useragents.log
+//
+// Reads standard input if file is not specified. It can be used with go:generate
+// to produce reports from your own traffic:
+//
+//	//go:generate go run github.com/mileusna/useragent/tools/uastats -top 50 -o uastats.txt useragents.log
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/mileusna/useragent"
+)
+
+// stats aggregated from parsed user agents
+type stats struct {
+	total    int
+	unknown  int
+	browsers map[string]int
+	oses     map[string]int
+	devices  map[string]int
+	fallback map[string]int // unrecognized user agents
+}
+
+func newStats() *stats {
+	return &stats{
+		browsers: map[string]int{},
+		oses:     map[string]int{},
+		devices:  map[string]int{},
+		fallback: map[string]int{},
+	}
+}
+
+func (s *stats) add(ua useragent.UserAgent) {
+	s.total++
+	if ua.IsUnknown() {
+		s.unknown++
+	}
+	if ua.Name == ua.Raw {
+		s.fallback[ua.Raw]++
+		s.browsers["(fallback)"]++
+	} else {
+		s.browsers[ua.Name]++
+	}
+	s.oses[ua.OS]++
+	s.devices[ua.DeviceType().String()]++
+}
+
+func (s *stats) merge(o *stats) {
+	s.total += o.total
+	s.unknown += o.unknown
+	for _, m := range []struct{ dst, src map[string]int }{
+		{s.browsers, o.browsers}, {s.oses, o.oses}, {s.devices, o.devices}, {s.fallback, o.fallback},
+	} {
+		for k, v := range m.src {
+			m.dst[k] += v
+		}
+	}
+}
+
+// collect parses user agents from r using n workers
+func collect(r io.Reader, n int) (*stats, error) {
+	lines := make(chan string, 1024)
+	results := make(chan *stats, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := newStats()
+			for line := range lines {
+				s.add(useragent.Parse(line))
+			}
+			results <- s
+		}()
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if line := sc.Text(); line != "" {
+			lines <- line
+		}
+	}
+	close(lines)
+	wg.Wait()
+	close(results)
+
+	total := newStats()
+	for s := range results {
+		total.merge(s)
+	}
+	return total, sc.Err()
+}
+
+func main() {
+	top := flag.Int("top", 10, "number of top entries in each list")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of parsing workers")
+	out := flag.String("o", "", "output file, default standard output")
+	flag.Parse()
+
+	in := os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	s, err := collect(in, *workers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	report(w, s, *top)
+}
+
+func report(w io.Writer, s *stats, top int) {
+	fmt.Fprintf(w, "user agents: %d\n", s.total)
+	if s.total == 0 {
+		return
+	}
+	fmt.Fprintf(w, "unknown:     %d (%.2f%%)\n", s.unknown, percent(s.unknown, s.total))
+	printTop(w, "browsers", s.browsers, s.total, top)
+	printTop(w, "operating systems", s.oses, s.total, top)
+	printTop(w, "device types", s.devices, s.total, top)
+	printTop(w, "unrecognized user agents", s.fallback, s.total, top)
+}
+
+func printTop(w io.Writer, title string, m map[string]int, total, top int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > top {
+		keys = keys[:top]
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, k := range keys {
+		name := k
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Fprintf(w, "%10d %6.2f%%  %s\n", m[k], percent(m[k], total), name)
+	}
+}
+
+func percent(n, total int) float64 {
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	const log = `Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36
+Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36
+
+Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1
+Go-http-client/1.1
+some garbage
+some garbage
+`
+	s, err := collect(strings.NewReader(log), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.total != 6 || s.unknown != 3 {
+		t.Error("expected 6 user agents, 3 unknown, got", s.total, s.unknown)
+	}
+	if s.browsers["Chrome"] != 2 || s.browsers["Safari"] != 1 || s.browsers["(fallback)"] != 2 {
+		t.Error("unexpected browsers", s.browsers)
+	}
+	if s.devices["desktop"] != 2 || s.devices["phone"] != 1 {
+		t.Error("unexpected devices", s.devices)
+	}
+	if s.fallback["some garbage"] != 2 {
+		t.Error("unexpected fallback", s.fallback)
+	}
+}
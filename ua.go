@@ -2,25 +2,67 @@ package useragent
 
 import (
 	"bytes"
-	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // UserAgent struct containing all data extracted from parsed user-agent string
 type UserAgent struct {
-	VersionNo   VersionNo
-	OSVersionNo VersionNo
-	URL         string
-	String      string
-	Name        string
-	Version     string
-	OS          string
-	OSVersion   string
-	Device      string
-	Mobile      bool
-	Tablet      bool
-	Desktop     bool
-	Bot         bool
+	VersionNo       VersionNo
+	OSVersionNo     VersionNo
+	URL             string   // the first of URLs, like bot info page
+	URLs            []string // all URLs sent, bots sometimes send info and contact pages
+	Raw             string   // raw user agent string
+	Name            string
+	Version         string
+	OS              string
+	OSVersion       string
+	OSDistro        string // Linux distribution, like Ubuntu or Fedora
+	OSDistroVersion string
+	WindowSystem    string // X11 or Wayland, set only by parser with EnableRawTokens
+	Device          string
+	DeviceVendor    string // like "Samsung", from the device database
+	DeviceModel     string // marketing name, like "Galaxy S21", from the device database
+	Mobile          bool
+	Tablet          bool
+	Desktop         bool
+	Bot             bool
+	EReader         bool
+	Arch            string
+	Locale          string
+	Region          string // country or region claimed by apps, like PE for Region/PE or ES for es_ES locale
+	AndroidBuild    string
+	HMSCore         string
+	WebView         bool
+	MaybeIPad       bool   // macOS user agent sent by iPad in desktop mode
+	DesktopMode     bool   // desktop user agent sent by mobile device, like iPad or Samsung DeX
+	AppName         string // app of the in-app browser or desktop app, like Instagram or Slack
+	AppVersion      string
+	BrowserName     string // browser embedded by the app, or Name, empty for bots and tools
+	BrowserVersion  string
+	Engine          string // browser engine, like Blink or WebKit
+	EngineVersion   string
+	Electron        string // Electron version of desktop apps
+	Screen          Screen // screen size sent by in-app browsers, like Instagram
+	Tool            bool   // HTTP client library, SDK or command line tool
+	TextBrowser     bool   // text-mode browser, like Lynx or w3m
+	Prefetch        bool   // page preview or prefetch agent, not initiated by the user
+	AutomationTool  string // headless browser or automation framework, like Selenium
+	Category        string // client category, like CategoryScanner
+	Truncated       bool   // user agent longer than the parser limit, only its prefix is parsed
+	BotReason       string
+	BotContact      string     // contact email sent by bots and scripts, like spider-feedback@bytedance.com
+	VerifiedBot     bool       // set by verify package
+	Anomalies       []string   // structural red flags, like AnomalyTruncated
+	Debug           *DebugInfo // set only by parser with EnableDebugInfo
+	Tokens          []Token    // set only by parser with EnableRawTokens
+
+	// Deprecated: use Raw. String will be removed in v2, when UserAgent
+	// will implement fmt.Stringer returning Pretty summary.
+	String string
+
+	deviceType DeviceType
 }
 
 // Constants for browsers and operating systems for easier comparison
@@ -38,13 +80,35 @@ const (
 	BlackBerry     = "BlackBerry"
 	CrOS           = "CrOS"
 	Harmony        = "Harmony"
+	KaiOS          = "KaiOS"
+	Series40       = "Series 40"
+	Series60       = "Series 60"
+	Tizen          = "Tizen"
+	WatchOS        = "watchOS"
+	RokuOS         = "Roku OS"
+	OrbisOS        = "Orbis OS"
+	VisionOS       = "visionOS"
+	WearOS         = "Wear OS"
+	SailfishOS     = "Sailfish OS"
+	UbuntuTouch    = "Ubuntu Touch"
+	PostmarketOS   = "postmarketOS"
+	Mobian         = "Mobian"
 
 	Opera            = "Opera"
 	OperaMini        = "Opera Mini"
 	OperaTouch       = "Opera Touch"
+	OperaGX          = "Opera GX"
+	OperaCrypto      = "Opera Crypto"
 	Chrome           = "Chrome"
 	HeadlessChrome   = "Headless Chrome"
 	Firefox          = "Firefox"
+	FirefoxFocus     = "Firefox Focus"
+	FirefoxKlar      = "Firefox Klar"
+	Waterfox         = "Waterfox"
+	LibreWolf        = "LibreWolf"
+	PaleMoon         = "Pale Moon"
+	SeaMonkey        = "SeaMonkey"
+	IceCat           = "IceCat"
 	InternetExplorer = "Internet Explorer"
 	Safari           = "Safari"
 	Edge             = "Edge"
@@ -54,12 +118,25 @@ const (
 	Mozilla          = "Mozilla"
 	Msie             = "MSIE"
 	SamsungBrowser   = "Samsung Browser"
+	Silk             = "Silk"
+	OculusBrowser    = "Oculus Browser"
+	NintendoBrowser  = "NintendoBrowser"
+	Whale            = "Whale"
+	DuckDuckGo       = "DuckDuckGo"
+	Ecosia           = "Ecosia"
+	Startpage        = "Startpage"
+	Lynx             = "Lynx"
+	W3m              = "w3m"
+	ELinks           = "ELinks"
+	Links            = "Links"
 
 	GoogleAdsBot        = "Google Ads Bot"
 	Googlebot           = "Googlebot"
 	Twitterbot          = "Twitterbot"
 	FacebookExternalHit = "facebookexternalhit"
 	Applebot            = "Applebot"
+	ApplebotExtended    = "Applebot-Extended"
+	AppleNewsBot        = "AppleNewsBot"
 	Bingbot             = "Bingbot"
 	YandexBot           = "YandexBot"
 	YandexAdNet         = "YandexAdNet"
@@ -67,6 +144,15 @@ const (
 	FacebookApp  = "Facebook App"
 	InstagramApp = "Instagram App"
 	TiktokApp    = "TikTok App"
+	NaverApp     = "Naver App"
+	DaumApp      = "Daum App"
+	KakaoTalkApp = "KakaoTalk App"
+	LineApp      = "LINE App"
+	WeChatApp    = "WeChat App"
+	QQApp        = "QQ App"
+	WeiboApp     = "Weibo App"
+	AlipayApp    = "Alipay App"
+	BaiduApp     = "Baidu App"
 
 	Version = "Version"
 	Mobile  = "Mobile"
@@ -75,23 +161,160 @@ const (
 	tablet = "tablet"
 )
 
+// geckoForks maps Firefox fork tokens to browser names
+var geckoForks = map[string]string{
+	"Focus":    FirefoxFocus,
+	"Klar":     FirefoxKlar,
+	Waterfox:   Waterfox,
+	LibreWolf:  LibreWolf,
+	"PaleMoon": PaleMoon,
+	SeaMonkey:  SeaMonkey,
+	IceCat:     IceCat,
+}
+
 // Parse user agent string returning UserAgent struct
 func Parse(userAgent string) UserAgent {
+	return defaultParser().Parse(userAgent)
+}
+
+// Parse user agent string returning UserAgent struct
+func (p *Parser) Parse(userAgent string) UserAgent {
+	ua, _ := p.parseFallback(userAgent)
+	return ua
+}
+
+// parseFallback parses user agent like Parse, reporting whether the name is
+// picked by fallback because user agent is not recognized
+func (p *Parser) parseFallback(userAgent string) (UserAgent, bool) {
+	if p.hooks == nil {
+		return p.detect(userAgent)
+	}
+	return p.parseWithHooks(userAgent)
+}
+
+// isEmpty returns true for empty or whitespace only user agent, or "-"
+// written to access logs for requests without User-Agent header
+func isEmpty(userAgent string) bool {
+	s := strings.TrimSpace(userAgent)
+	return s == "" || s == "-"
+}
+
+// truncate cuts user agent to at most limit bytes at the last space,
+// or at the limit if there is no space
+func truncate(userAgent string, limit int) string {
+	if i := strings.LastIndexByte(userAgent[:limit+1], ' '); i > 0 {
+		return userAgent[:i]
+	}
+	return userAgent[:limit]
+}
+
+// detect parses user agent string, reporting whether the name is picked
+// by fallback because user agent is not recognized
+func (p *Parser) detect(userAgent string) (UserAgent, bool) {
 	ua := UserAgent{
+		Raw:    userAgent,
 		String: userAgent,
 	}
 
-	tokens := parse([]byte(userAgent))
-	ua.URL = tokens.url
+	// empty user agent and "-" placeholder of access logs are unknown
+	if isEmpty(userAgent) {
+		return ua, false
+	}
+
+	// multi-kilobyte user agents are cut at the token boundary,
+	// browser and OS tokens are at the start
+	if limit := p.parseLimit(); limit > 0 && len(userAgent) > limit {
+		userAgent = truncate(userAgent, limit)
+		ua.Truncated = true
+	}
+
+	// invalid UTF-8 sequences are replaced, Raw keeps the original
+	if !utf8.ValidString(userAgent) {
+		userAgent = strings.ToValidUTF8(userAgent, "\uFFFD")
+	}
+	tokens := p.parse(userAgent)
+	if len(tokens.urls) > 0 {
+		ua.URL, ua.URLs = tokens.urls[0], tokens.urls
+	}
+	ua.BotContact = tokens.findContact()
+	ua.Locale = tokens.findLocale()
+	ua.Region = tokens.findRegion(ua.Locale)
 
 	// OS lookup
 	switch {
+	// KaiOS sends Android token as well
+	case tokens.existsAny("KAIOS", KaiOS):
+		ua.OS = KaiOS
+		_, ua.OSVersion = tokens.getAny("KAIOS", KaiOS)
+		ua.Mobile = true
+
+	// HarmonyOS on Huawei devices sends Android token as well,
+	// Android version is not HarmonyOS version so it is omitted
+	case tokens.exists("HarmonyOS"):
+		ua.OS = Harmony
+		osIndex, _ := tokens.getIndexValue("HarmonyOS")
+		ua.Device, ua.AndroidBuild = tokens.findAndroidDevice(osIndex)
+		ua.Tablet = tokens.exists(Tablet) || p.isTabletModel(ua.Device)
+		ua.Mobile = true
+
+	// Roku SDK sends Roku OS version as "Roku/DVP-12.0", or with player model
+	// like "Roku4640X/DVP-7.70"
+	case tokens.findRoku() != "":
+		ua.OS = RokuOS
+		ua.Device = tokens.findRoku()
+		ua.OSVersion = strings.TrimPrefix(tokens.get(ua.Device), "DVP-")
+		if model := ua.Device[len("Roku"):]; model != "" {
+			ua.Device = "Roku " + model
+		}
+		ua.deviceType = DeviceTV
+
+	// Meta Quest headsets run Android, browser in desktop mode sends
+	// "X11; Linux x86_64; Quest 2" without Android version
+	case tokens.exists("OculusBrowser") || tokens.findQuest() != "":
+		ua.OS = Android
+		ua.OSVersion = tokens.get(Android)
+		ua.Device = "Meta Quest"
+		ua.deviceType = DeviceHeadset
+
 	case tokens.exists(Android):
 		ua.OS = Android
 		var osIndex int
 		osIndex, ua.OSVersion = tokens.getIndexValue(Android)
-		ua.Tablet = strings.Contains(strings.ToLower(ua.String), tablet)
-		ua.Device = tokens.findAndroidDevice(osIndex)
+		// Wear OS token is sent between Android version and device name
+		if wearIndex, _ := tokens.getIndexValue(WearOS); wearIndex == osIndex+1 {
+			osIndex = wearIndex
+		}
+		ua.Device, ua.AndroidBuild = tokens.findAndroidDevice(osIndex)
+		// Tablet token is the first word of models like "Tablet Phone X1" too,
+		// it is ignored when such phone sends Mobile token
+		ua.Tablet = tokens.exists(Tablet) && !(strings.HasPrefix(ua.Device, Tablet+" ") && tokens.existsAny(Mobile, MobileSafari)) ||
+			p.isTabletModel(ua.Device)
+		switch {
+		case tokens.existsAny(WearOS, "watch"):
+			ua.deviceType = DeviceWearable
+		case isTVDevice(ua.Device):
+			ua.deviceType = DeviceTV
+		case tokens.exists("CrKey") || tokens.get("DeviceType") == "AndroidTV":
+			// Chromecast with Google TV doesn't send device model
+			if ua.Device == "" {
+				ua.Device = "Chromecast"
+			}
+			ua.deviceType = DeviceTV
+		}
+
+	case tokens.existsAny("Apple Watch", "Watch"):
+		ua.OS = WatchOS
+		ua.OSVersion = tokens.findMacOSVersion()
+		ua.Device = "Apple Watch"
+		ua.deviceType = DeviceWearable
+
+	// Apple Vision Pro, Safari sends macOS user agent,
+	// apps and web views send visionOS token or hardware identifier
+	case tokens.isVisionOS():
+		ua.OS = VisionOS
+		ua.OSVersion = tokens.findVisionOSVersion()
+		ua.Device = "Apple Vision Pro"
+		ua.deviceType = DeviceHeadset
 
 	case tokens.exists("iPhone"):
 		ua.OS = IOS
@@ -99,16 +322,46 @@ func Parse(userAgent string) UserAgent {
 		ua.Device = "iPhone"
 		ua.Mobile = true
 
+	// iPod touch sends iPhone OS version, but not iPhone token
+	case tokens.existsAny("iPod touch", "iPod"):
+		ua.OS = IOS
+		ua.OSVersion = tokens.findMacOSVersion()
+		ua.Device = "iPod touch"
+		ua.Mobile = true
+
 	case tokens.exists("iPad"):
 		ua.OS = IOS
 		ua.OSVersion = tokens.findMacOSVersion()
 		ua.Device = "iPad"
 		ua.Tablet = true
 
+	// PlayStation 4 and 5 run Orbis OS and send its version with the model,
+	// like "PlayStation 4 5.55" or "PlayStation 5/2.26"
+	case tokens.startsWith("PlayStation ") || tokens.startsWith("PLAYSTATION "):
+		var model, firmware string
+		ua.Device, model, firmware = tokens.findPlayStation()
+		if model == "4" || model == "5" {
+			ua.OS = OrbisOS
+			ua.OSVersion = firmware
+		}
+		ua.deviceType = DeviceConsole
+
+	// Nintendo Switch, 3DS and Wii U, like "Nintendo Switch; WifiWebAuthApplet"
+	// sent by the Wi-Fi login applet
+	case tokens.findNintendo() != "":
+		ua.Device = tokens.findNintendo()
+		ua.deviceType = DeviceConsole
+
 	case tokens.exists(WindowsNT):
 		ua.OS = Windows
 		ua.OSVersion = tokens.get(WindowsNT)
+		ua.Arch = tokens.findWindowsArch()
+		ua.Device = tokens.findSurface()
 		ua.Desktop = true
+		if tokens.exists("Xbox") {
+			ua.Device = tokens.findXbox()
+			ua.deviceType = DeviceConsole
+		}
 
 	case tokens.exists(WindowsPhoneOS):
 		ua.OS = WindowsPhone
@@ -120,7 +373,57 @@ func Parse(userAgent string) UserAgent {
 		ua.OSVersion = tokens.findMacOSVersion()
 		ua.Desktop = true
 
-	case tokens.exists(Linux):
+	// native Apple apps, OS version is derived from Darwin version
+	case tokens.exists("CFNetwork") && tokens.exists("Darwin"):
+		ua.OS, ua.OSVersion, ua.Arch = tokens.findDarwinOS()
+		ua.Desktop = ua.OS == MacOS
+
+	case tokens.exists("Series40"):
+		ua.OS = Series40
+		ua.Device = tokens.findNokiaDevice()
+		ua.Mobile = true
+
+	case tokens.existsAny("Series60", "SymbianOS"):
+		ua.OS = Series60
+		if ua.OSVersion = tokens.get("Series60"); ua.OSVersion == "" {
+			ua.OSVersion = tokens.get("SymbianOS")
+		}
+		ua.Device = tokens.findNokiaDevice()
+		ua.Mobile = true
+
+	// Tizen sends Linux token as well
+	case tokens.exists(Tizen):
+		ua.OS = Tizen
+		var osIndex int
+		osIndex, ua.OSVersion = tokens.getIndexValue(Tizen)
+		ua.Device, _ = tokens.findAndroidDevice(osIndex)
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+
+	// mobile Linux, Sailfish sends "Sailfish 4.4" or only Sailfish token,
+	// Ubuntu Touch sends "Ubuntu 16.04 like Android 9" or "Ubuntu; Mobile"
+	case tokens.startsWith("Sailfish"):
+		ua.OS = SailfishOS
+		ua.OSVersion = findVersion(tokens.findPrefixVersion("Sailfish"))
+		ua.Mobile = true
+
+	case tokens.findUbuntuTouch() != "" || tokens.exists("Ubuntu") && tokens.existsAny(Mobile, Tablet):
+		ua.OS = UbuntuTouch
+		ua.OSVersion = tokens.findUbuntuTouch()
+		ua.Tablet = tokens.exists(Tablet)
+		ua.Mobile = !ua.Tablet
+
+	case tokens.existsAny(PostmarketOS, Mobian):
+		ua.OS, ua.OSVersion = tokens.getAny(PostmarketOS, Mobian)
+		ua.Mobile = true
+
+	// Chromecast sends Linux token, or only CrKey from the Cast SDK
+	case tokens.exists("CrKey"):
+		ua.OS = Linux
+		ua.OSVersion = tokens.get(Linux)
+		ua.Device = "Chromecast"
+		ua.deviceType = DeviceTV
+
+	case tokens.exists(Linux) || tokens.get("GNU") == Linux:
 		ua.OS = Linux
 		ua.OSVersion = tokens.get(Linux)
 		ua.Desktop = true
@@ -146,6 +449,37 @@ func Parse(userAgent string) UserAgent {
 		ua.Mobile = true
 	}
 
+	ua.Screen = tokens.findScreen()
+
+	if ua.IsAndroid() || ua.OS == Harmony {
+		ua.HMSCore = tokens.findHMSCore()
+		ua.WebView = tokens.exists("wv")
+	}
+
+	// distribution tokens are removed before the browser lookup
+	if ua.OS == Linux {
+		ua.OSDistro, ua.OSDistroVersion = tokens.findDistro()
+	}
+
+	// in-car browsers and smart appliances
+	if dev := tokens.findEmbedded(); dev != "" {
+		ua.Device = dev
+		ua.deviceType = DeviceEmbedded
+	}
+
+	// e-readers are reported as tablets
+	if dev := tokens.findEReader(ua.Device); dev != "" {
+		ua.Device = dev
+		ua.EReader = true
+		ua.Tablet = true
+		ua.Desktop = false
+	}
+
+	// name is picked from the remaining tokens, user agent is not recognized
+	fallback := false
+	// browser token with the highest precedence
+	browser := tokens.findPreferred(p.precedence())
+
 	switch {
 	case tokens.exists(Googlebot):
 		ua.Name = Googlebot
@@ -154,7 +488,7 @@ func Parse(userAgent string) UserAgent {
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
 	case tokens.existsAny("GoogleProber", "GoogleProducer"):
-		if name := tokens.findBestMatch(false); name != "" {
+		if name := tokens.findBestMatch(false, &p.fallback); name != "" {
 			ua.Name = name
 		}
 		ua.Bot = true
@@ -171,69 +505,141 @@ func Parse(userAgent string) UserAgent {
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 		ua.OS = ""
 
+	// robots.txt token for opting out of AI training on content crawled by
+	// Applebot, sent only by tools checking the rules
+	case tokens.exists(ApplebotExtended):
+		ua.Name = ApplebotExtended
+		ua.Version = tokens.get(ApplebotExtended)
+		ua.Bot = true
+		ua.Category = CategoryAICrawler
+		ua.OS, ua.OSVersion = "", ""
+
+	case tokens.exists(AppleNewsBot):
+		ua.Name = AppleNewsBot
+		ua.Version = tokens.get(AppleNewsBot)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+		ua.OS, ua.OSVersion = "", ""
+
+	// iMessage link previews, sent as Safari with Facebook and Twitter
+	// crawler tokens, or by WebKit networking process of Messages app
+	case tokens.exists(FacebookExternalHit) && tokens.exists("Facebot Twitterbot"),
+		tokens.exists("com.apple.WebKit.Networking"):
+		ua.Name = IMessagePreview
+		ua.Bot = true
+		ua.Prefetch = true
+		if tokens.exists(FacebookExternalHit) {
+			// the same macOS user agent is sent from any device
+			ua.OS, ua.OSVersion = "", ""
+		}
+
+	// page preview and prefetch agents, not initiated by the user
+	case tokens.existsAny(prefetchAgents...):
+		var key string
+		key, ua.Version = tokens.getAny(prefetchAgents...)
+		ua.Name = prefetchNames[key]
+		ua.Prefetch = true
+		// previews are rendered by crawlers, prefetch proxy fetches on behalf of the user
+		ua.Bot = key != "Chrome Privacy Preserving Prefetch Proxy"
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+
+	// privacy browsers, DuckDuckGo for Windows is based on WebView2 and sends
+	// Edg token as well
+	case tokens.exists("Ddg"):
+		ua.Name = DuckDuckGo
+		ua.Version = tokens.get("Ddg")
+
+	case tokens.existsWord(DuckDuckGo):
+		ua.Name = DuckDuckGo
+		ua.Version = tokens.getWord(DuckDuckGo)
+
+	case tokens.startsWith("Ecosia "):
+		ua.Name = Ecosia
+		ua.Version = tokens.findEcosiaVersion()
+
+	case tokens.exists("StartpagePrivateSearch"):
+		ua.Name = Startpage
+		ua.Version = tokens.get("StartpagePrivateSearch")
+
 	case tokens.get(OperaMini) != "":
 		ua.Name = OperaMini
 		ua.Version = tokens.get(OperaMini)
 		ua.Mobile = true
 
-	case tokens.get("OPR") != "":
-		ua.Name = Opera
-		ua.Version = tokens.get("OPR")
+	// Opera, Opera GX and Opera Crypto, version from OPR token when present
+	case browser == "OPR" || browser == "OPX":
+		ua.Name = tokens.findOperaEdition()
+		if ua.Version = tokens.get("OPR"); ua.Version == "" {
+			ua.Version = tokens.get("OPX")
+		}
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
-	case tokens.get("OPT") != "":
+	case browser == "OPT":
 		ua.Name = OperaTouch
 		ua.Version = tokens.get("OPT")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
 	// Opera on iOS
-	case tokens.get("OPiOS") != "":
+	case browser == "OPiOS":
 		ua.Name = Opera
 		ua.Version = tokens.get("OPiOS")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
 	// Chrome on iOS
-	case tokens.get("CriOS") != "":
+	case browser == "CriOS":
 		ua.Name = Chrome
 		ua.Version = tokens.get("CriOS")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
+	// Firefox forks and Focus, sent along with Firefox or Chrome tokens
+	case tokens.existsAny("Focus", "Klar", Waterfox, LibreWolf, "PaleMoon", SeaMonkey, IceCat):
+		var key string
+		key, ua.Version = tokens.getAny("Focus", "Klar", Waterfox, LibreWolf, "PaleMoon", SeaMonkey, IceCat)
+		ua.Name = geckoForks[key]
+		// Waterfox G releases, like G5.0.1
+		ua.Version = strings.TrimPrefix(ua.Version, "G")
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+		ua.Tablet = tokens.exists(Tablet)
+
 	// Firefox on iOS
-	case tokens.get("FxiOS") != "":
+	case browser == "FxiOS":
 		ua.Name = Firefox
 		ua.Version = tokens.get("FxiOS")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
-	case tokens.get(Firefox) != "":
+	case browser == Firefox:
 		ua.Name = Firefox
 		ua.Version = tokens.get(Firefox)
 		ua.Mobile = tokens.exists(Mobile)
 		ua.Tablet = tokens.exists(Tablet)
 
-	case tokens.get(Vivaldi) != "":
+	// Vivaldi on Android tablets doesn't send Mobile token
+	case browser == Vivaldi:
 		ua.Name = Vivaldi
 		ua.Version = tokens.get(Vivaldi)
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+		ua.Tablet = ua.Tablet || ua.OS == Android && !ua.Mobile
 
 	case tokens.exists(Msie):
 		ua.Name = InternetExplorer
 		ua.Version = tokens.get(Msie)
 
-	case tokens.get("EdgiOS") != "":
+	case browser == "EdgiOS":
 		ua.Name = Edge
 		ua.Version = tokens.get("EdgiOS")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
-	case tokens.get(Edge) != "":
+	case browser == Edge:
 		ua.Name = Edge
 		ua.Version = tokens.get(Edge)
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
-	case tokens.get("Edg") != "":
+	case browser == "Edg":
 		ua.Name = Edge
 		ua.Version = tokens.get("Edg")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
-	case tokens.get("EdgA") != "":
+	case browser == "EdgA":
 		ua.Name = Edge
 		ua.Version = tokens.get("EdgA")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
@@ -255,11 +661,38 @@ func Parse(userAgent string) UserAgent {
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 		ua.Bot = true
 
-	case tokens.get("SamsungBrowser") != "":
+	// Meta Quest browser sends SamsungBrowser token as well
+	case tokens.exists("OculusBrowser"):
+		ua.Name = OculusBrowser
+		ua.Version = tokens.get("OculusBrowser")
+
+	case browser == "SamsungBrowser":
 		ua.Name = SamsungBrowser
 		ua.Version = tokens.get("SamsungBrowser")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
-		ua.OS = Android
+		switch {
+		// desktop Linux user agent is sent in desktop mode, by default on
+		// Galaxy Tab, which can't be told apart from phones, and by Samsung
+		// DeX, which sends DeX token
+		case ua.OS == Linux:
+			ua.OS, ua.OSVersion = Android, ""
+			ua.DesktopMode = true
+			if tokens.exists("DeX") {
+				ua.deviceType = DeviceDesktop
+			}
+		case ua.OS != Tizen:
+			ua.OS = Android
+		}
+
+	// headless browsers and automation framework clients, Splash sends
+	// only Safari version
+	case tokens.existsAny(automationClients...):
+		key, version := tokens.getAny(automationClients...)
+		ua.Name = automationNames[key]
+		if ua.Name != Splash {
+			ua.Version = version
+		}
+		ua.Bot = true
 
 	case tokens.get("HeadlessChrome") != "":
 		ua.Name = HeadlessChrome
@@ -279,9 +712,9 @@ func Parse(userAgent string) UserAgent {
 
 	case tokens.exists("XiaoMi"):
 		miui := tokens.get("XiaoMi")
-		if strings.HasPrefix(miui, "MiuiBrowser") {
+		if hasPrefixFold(miui, "miuibrowser") {
 			ua.Name = "Miui Browser"
-			ua.Version = strings.TrimPrefix(miui, "MiuiBrowser/")
+			ua.Version = strings.TrimPrefix(miui[len("MiuiBrowser"):], "/")
 			ua.Mobile = true
 		}
 
@@ -301,23 +734,135 @@ func Parse(userAgent string) UserAgent {
 		ua.Name = TiktokApp
 		ua.Version = tokens.get("app_version")
 
-	case tokens.get("HuaweiBrowser") != "":
+	// Korean and Japanese portal and messenger apps, Naver app on Android
+	// sends Whale token as well
+	case tokens.exists("NAVER"):
+		ua.Name = NaverApp
+		ua.Version = tokens.findNaverVersion()
+
+	case tokens.exists("DaumApps"):
+		ua.Name = DaumApp
+		ua.Version = tokens.get("DaumApps")
+
+	case tokens.startsWith("KAKAOTALK "):
+		ua.Name = KakaoTalkApp
+		ua.Version = tokens.findPrefixVersion("KAKAOTALK ")
+
+	case tokens.findLine() != "":
+		ua.Name = LineApp
+		ua.Version = tokens.findLine()
+
+	// Chinese apps load pages in the embedded WebView, on desktop too
+	case tokens.exists("MicroMessenger"):
+		ua.Name = WeChatApp
+		ua.Version = tokens.get("MicroMessenger")
+		ua.WebView = true
+
+	case tokens.existsWord("QQ"):
+		ua.Name = QQApp
+		ua.Version = tokens.getWord("QQ")
+		ua.WebView = true
+
+	case tokens.exists("Weibo"):
+		ua.Name = WeiboApp
+		ua.Version = tokens.findWeiboVersion()
+		ua.WebView = true
+
+	case tokens.exists("AlipayClient"):
+		ua.Name = AlipayApp
+		ua.Version = tokens.get("AlipayClient")
+		ua.WebView = true
+
+	case tokens.existsWord("baiduboxapp"):
+		ua.Name = BaiduApp
+		ua.Version = tokens.getWord("baiduboxapp")
+		ua.WebView = true
+
+	case tokens.exists("Whale"):
+		ua.Name = Whale
+		ua.Version = tokens.get("Whale")
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+
+	case browser == "HuaweiBrowser":
 		ua.Name = "Huawei Browser"
 		ua.Version = tokens.get("HuaweiBrowser")
 		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
 
+	// Silk on Kindle Fire tablets
+	case browser == "Silk":
+		ua.Name = Silk
+		ua.Version = tokens.get("Silk")
+		ua.Tablet = true
+
 	case tokens.exists(BlackBerry):
 		ua.Name = BlackBerry
 		ua.Version = tokens.get(Version)
 
+	// browser token without builtin detection, set by Parser.SetPrecedence
+	case browser != "" && !builtinBrowserToken(browser):
+		ua.Name = browser
+		ua.Version = tokens.get(browser)
+		ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
+
+	// Nokia browsers on Series 40 and Series 60
+	case tokens.existsAny("S40OviBrowser", "BrowserNG"):
+		ua.Name = "Nokia Browser"
+		if ua.Version = tokens.get("S40OviBrowser"); ua.Version == "" {
+			ua.Version = tokens.get("BrowserNG")
+		}
+		ua.Mobile = true
+
+	// NetFront based browser of Nintendo consoles, old 3DS sends only Version
+	case strings.Contains(ua.Device, "Nintendo"):
+		ua.Name = NintendoBrowser
+		if ua.Version = tokens.findNintendoBrowser(); ua.Version == "" {
+			ua.Version = tokens.get(Version)
+		}
+
 	case tokens.exists(NetFront):
 		ua.Name = NetFront
 		ua.Version = tokens.get(NetFront)
 		ua.Mobile = true
 
+	// text-mode browsers, device type is unknown and Links sends
+	// version without slash, like "Links (2.20.2; Linux ...)"
+	case tokens.existsAny(Lynx, W3m, ELinks, Links):
+		ua.Name, ua.Version = tokens.getAny(Lynx, W3m, ELinks, Links)
+		if ua.Name == Links && ua.Version == "" {
+			ua.Version = tokens.findLinksVersion()
+		}
+		ua.TextBrowser = true
+		ua.Desktop = false
+		// terminal size in characters, like 80x24
+		ua.Screen = Screen{}
+
+	// Apple HTTP stack used by native apps
+	case tokens.exists("CFNetwork"):
+		ua.Name = "CFNetwork"
+		ua.Version = tokens.get("CFNetwork")
+		ua.AppName, ua.AppVersion = tokens.findCFNetworkApp()
+		ua.Tool = true
+
+	// Roku SDK, version is Roku OS version
+	case ua.OS == RokuOS:
+		ua.Name = "Roku"
+		ua.Version = ua.OSVersion
+
+	// Android and Java HTTP stacks
+	case tokens.existsAny("Dalvik", "okhttp"):
+		ua.Name, ua.Version = tokens.getAny("Dalvik", "okhttp")
+		ua.Tool = true
+
+	// Electron desktop apps, name remains Chrome
+	case tokens.exists("Electron"):
+		ua.Name = Chrome
+		ua.Version = tokens.get(Chrome)
+		ua.Electron = tokens.get("Electron")
+		ua.AppName, ua.AppVersion = tokens.findElectronApp()
+
 	// if Chrome and Safari defined, find any other token sent descr
 	case tokens.exists(Chrome) && tokens.exists(Safari):
-		name := tokens.findBestMatch(true)
+		name := tokens.findBestMatch(true, &p.fallback)
 		if name != "" {
 			ua.Name = name
 			ua.Version = tokens.get(name)
@@ -350,14 +895,24 @@ func Parse(userAgent string) UserAgent {
 			ua.Name = "Android browser"
 			ua.Version = tokens.get(Version)
 			ua.Mobile = true
+		} else if ua.OS == Tizen && tokens.get(Version) != "" {
+			ua.Name = "Tizen browser"
+			ua.Version = tokens.get(Version)
+		} else if strings.HasPrefix(ua.Device, "PlayStation") {
+			// PlayStation 3 and 4 send no browser token
+			ua.Name = "PlayStation browser"
 		} else {
-			if name := tokens.findBestMatch(false); name != "" {
+			fallback = true
+			if name := tokens.findBestMatch(false, &p.fallback); name != "" {
 				ua.Name = name
 				ua.Version = tokens.get(name)
 			} else {
-				ua.Name = ua.String
+				ua.Name = ua.Raw
+			}
+			if containsFold(ua.Name, "bot") {
+				ua.Bot = true
+				ua.BotReason = BotReasonKeyword
 			}
-			ua.Bot = strings.Contains(strings.ToLower(ua.Name), "bot")
 			// If mobile flag has already been set, don't override it.
 			if !ua.Mobile {
 				ua.Mobile = tokens.existsAny(Mobile, MobileSafari)
@@ -365,62 +920,193 @@ func Parse(userAgent string) UserAgent {
 		}
 	}
 
+	// scanners, feed readers, package managers and other client categories
+	if c, ok := findClient(userAgent); ok {
+		cat := clientCategories[c.rank]
+		ua.Name = c.name
+		ua.Version = tokens.findClientVersion(c.token)
+		ua.Bot = cat.bot
+		if !cat.bot {
+			ua.BotReason = ""
+		}
+		ua.Tool = cat.tool
+		ua.Category = cat.category
+		fallback = false
+	}
+
+	// detection stage and index of the rule or matcher, reported in DebugInfo
+	stage, index := StageBuiltin, -1
+	if fallback {
+		stage = StageFallback
+	}
+
+	if p.extended && !ua.Bot {
+		if name, version := tokens.findExtendedBrowser(); name != "" {
+			ua.Name = name
+			ua.Version = version
+			stage = StageExtended
+		}
+	}
+
+	if !ua.Tool && !ua.Bot {
+		ua.Tool = isTool(ua.Name)
+	}
+
+	if tool := tokens.findAutomationTool(); tool != "" {
+		ua.AutomationTool = tool
+		ua.Bot = true
+	}
+
+	if len(p.rules) != 0 {
+		if index = p.applyRules(&ua, tokens); index >= 0 {
+			fallback = false
+			stage = StageRule
+		}
+	}
+
+	// regexp matchers are checked only if user agent is not recognized
+	if fallback && len(p.matchers) != 0 {
+		if ua, index = p.applyMatchers(ua); index >= 0 {
+			stage = StageMatcher
+		}
+	}
+
+	// vendor and marketing name of device model code, apps on Apple devices
+	// send hardware identifier, like iPhone13,2, Meta Quest sends the model
+	if db := p.deviceDB(); db != nil {
+		model := ua.Device
+		if id := tokens.findAppleModelID(); id != "" {
+			model = id
+		} else if id := tokens.findQuest(); id != "" {
+			model = id
+		}
+		if d, ok := db.Lookup(model); model != "" && ok {
+			ua.DeviceVendor, ua.DeviceModel = d.Vendor, d.Model
+		}
+	}
+
+	if p.debug {
+		ua.Debug = p.debugInfo(tokens, ua.Name, ua.Version, stage, index)
+	}
+	if p.rawTokens {
+		ua.Tokens = tokens.rawTokens()
+		ua.WindowSystem = tokens.findWindowSystem()
+	}
+
 	if ua.IsAndroid() {
 		ua.Mobile = true
 	}
 
-	// if tablet, switch mobile to off
-	if ua.Tablet {
-		ua.Mobile = false
+	// iPadOS 13+ requests desktop sites with macOS user agent,
+	// but some iOS tokens remain, iPhone in desktop mode sends them as well
+	if ua.OS == MacOS && tokens.isIOSDesktopMode() {
+		ua.MaybeIPad = true
+		ua.DesktopMode = true
+		ua.deviceType = DeviceTablet
 	}
 
+	// single device type, tablet switches mobile off
+	ua.setDeviceType()
+
 	// if not already bot, check some popular bots and whether URL is set
-	if !ua.Bot {
+	if !ua.Bot && ua.Category != CategoryFeedReader {
 		switch ua.Name {
 		case Twitterbot, FacebookExternalHit, "facebookcatalog":
 			ua.Bot = true
 		default:
-			ua.Bot = ua.URL != ""
+			// unrecognized clients without OS linking their info page are
+			// crawlers, apps embedding their home page send browser user agent
+			if isBotURL(ua.URL, ua.Name, ua.BotContact) || fallback && ua.OS == "" && isInfoURL(ua.Raw, ua.URL) {
+				ua.Bot = true
+				ua.BotReason = BotReasonURL
+			}
 		}
 	}
+	if ua.Bot && ua.BotReason == "" {
+		ua.BotReason = BotReasonKnown
+	}
+
+	ua.setChain(tokens)
+
+	if name, ok := p.aliases[ua.Name]; ok {
+		ua.Name = name
+	}
+	if name, ok := p.aliases[ua.BrowserName]; ok {
+		ua.BrowserName = name
+	}
 
 	ua.VersionNo = parseVersion(ua.Version)
 	ua.OSVersionNo = parseVersion(ua.OSVersion)
+	ua.Anomalies = findAnomalies(&ua)
 
-	return ua
+	return ua, fallback
 }
 
-// var buffPool = sync.Pool{New: func() interface{} {
-// 	return bytes.NewBuffer(make([]byte, 0, 30))
-// }}
+// delimiters are bytes which may end the token or switch tokenizer state,
+// multibyte characters are included since unicode spaces are delimiters
+var delimiters = func() (d [256]bool) {
+	for _, c := range []byte(");([]:/ ") {
+		d[c] = true
+	}
+	for c := utf8.RuneSelf; c < 256; c++ {
+		d[c] = true
+	}
+	return d
+}()
+
+// indexDelimiter returns index of the first delimiter in s, or len(s) if none
+func indexDelimiter(s string) int {
+	for i := 0; i < len(s); i++ {
+		if delimiters[s[i]] {
+			return i
+		}
+	}
+	return len(s)
+}
 
-func parse(userAgent []byte) properties {
+// parse splits user agent into tokens, it takes string so user agent is
+// not copied into byte slice before parsing
+func (p *Parser) parse(userAgent string) properties {
 	clients := properties{
 		list: make([]property, 0, 8),
 	}
 	slash := false
 	isURL := false
-	// buff := buffPool.Get().(*bytes.Buffer)
-	// val := buffPool.Get().(*bytes.Buffer)
-	// buff.Reset()
-	// val.Reset()
-
-	buff := bytes.NewBuffer(make([]byte, 0, 30))
-	val := bytes.NewBuffer(make([]byte, 0, 30))
+	buffers := p.getBuffers()
+	defer p.putBuffers(buffers)
+	buff := &buffers.buff
+	val := &buffers.val
 
 	addToken := func() {
 		if buff.Len() != 0 {
-			s := string(bytes.TrimSpace(buff.Bytes()))
-			if !ignore(s) {
-				if isURL {
-					clients.url = strings.TrimPrefix(s, "+")
-					return
+			s := intern(bytes.TrimSpace(buff.Bytes()))
+			if !isURL && val.Len() == 0 && isLocale(s) {
+				// keep only the first locale token
+				if clients.locale == "" {
+					clients.locale = s
+				}
+			} else if p.ignored(s) {
+				if p.debug {
+					clients.discarded = append(clients.discarded, s)
 				}
+			} else if isURL {
+				// text before the URL in the same token, like "+" or "see", is dropped
+				if i := strings.Index(s, "http"); i >= 0 {
+					clients.urls = append(clients.urls, s[i:])
+				}
+			} else {
+				var prop property
 				if val.Len() == 0 {
 					// if value don't exists, try to get version from the token
-					clients.list = append(clients.list, checkVer(s))
+					prop = checkVer(s)
 				} else {
-					clients.list = append(clients.list, property{Key: s, Value: string(bytes.TrimSpace(val.Bytes()))})
+					prop = property{Key: s, Value: intern(bytes.TrimSpace(val.Bytes()))}
+				}
+				prop.Key = foldToken(prop.Key)
+				if prop, ok := p.filter(prop); ok {
+					clients.list = append(clients.list, prop)
+				} else if p.debug {
+					clients.discarded = append(clients.discarded, s)
 				}
 			}
 		}
@@ -433,7 +1119,28 @@ func parse(userAgent []byte) properties {
 	parOpen := false
 	braOpen := false
 
-	for i, c := range userAgent {
+	for i := 0; i < len(userAgent); i++ {
+		c := userAgent[i]
+		// copy the run of bytes up to the next delimiter at once, delimiters
+		// which are copied in the current state only start the run
+		if !delimiters[c] || (c == ' ' && !slash && !isURL) || (c == '/' && (slash || isURL)) {
+			j := i + 1 + indexDelimiter(userAgent[i+1:])
+			if slash {
+				val.WriteString(userAgent[i:j])
+			} else {
+				buff.WriteString(userAgent[i:j])
+			}
+			i = j - 1
+			continue
+		}
+		// unicode spaces, like no-break or ideographic space, are treated as space,
+		// other multibyte characters are copied byte by byte
+		if c >= utf8.RuneSelf {
+			if r, size := utf8.DecodeRuneInString(userAgent[i:]); unicode.IsSpace(r) {
+				c = ' '
+				i += size - 1
+			}
+		}
 		switch {
 		case c == 41: // )
 			addToken()
@@ -469,6 +1176,10 @@ func parse(userAgent []byte) properties {
 		case slash && c == 32:
 			addToken()
 
+		case isURL && c == 32:
+			// URL ends at the space, text that follows is the next token
+			addToken()
+
 		case slash:
 			val.WriteByte(c)
 
@@ -477,7 +1188,10 @@ func parse(userAgent []byte) properties {
 				buff.WriteByte(c)
 				isURL = true
 			} else {
-				if ignore(buff.String()) {
+				if p.ignored(buff.String()) {
+					if p.debug {
+						clients.discarded = append(clients.discarded, buff.String())
+					}
 					buff.Reset()
 				} else {
 					slash = true
@@ -490,8 +1204,6 @@ func parse(userAgent []byte) properties {
 	}
 	addToken()
 
-	// buffPool.Put(buff)
-	// buffPool.Put(val)
 	return clients
 }
 
@@ -502,7 +1214,7 @@ func checkVer(s string) property {
 	}
 
 	switch s[:i] {
-	case Linux, WindowsNT, WindowsPhoneOS, Msie, Android, "OpenHarmony":
+	case Linux, WindowsNT, WindowsPhoneOS, Msie, Android, "OpenHarmony", Tizen:
 		return property{Key: s[:i], Value: s[i+1:]}
 	case "CrOS x86_64", "CrOS aarch64", "CrOS armv7l":
 		j := strings.LastIndex(s[:i], " ")
@@ -512,14 +1224,43 @@ func checkVer(s string) property {
 	}
 }
 
+// ignoredTokens are skipped by the parser, they don't identify the client
+var ignoredTokens = []string{"KHTML, like Gecko", "U", "compatible", Mozilla, "WOW64", "Browser"}
+
 // ignore returns true if token should be ignored
 func ignore(s string) bool {
-	switch s {
-	case "KHTML, like Gecko", "U", "compatible", Mozilla, "WOW64", "en", "en-us", "en-gb", "ru-ru", "Browser":
+	for _, t := range ignoredTokens {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isLocale returns true if token looks like language tag, like en, en-us or fr_FR
+func isLocale(s string) bool {
+	if s == "wv" { // Android WebView token
+		return false
+	}
+	i := 0
+	for i < len(s) && s[i] >= 'a' && s[i] <= 'z' {
+		i++
+	}
+	if i < 2 || i > 3 {
+		return false
+	}
+	if i == len(s) {
 		return true
-	default:
+	}
+	if len(s) != i+3 || (s[i] != '-' && s[i] != '_') {
 		return false
 	}
+	for _, c := range []byte(s[i+1:]) {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
 }
 
 type property struct {
@@ -527,8 +1268,10 @@ type property struct {
 	Value string
 }
 type properties struct {
-	list []property
-	url  string
+	list      []property
+	urls      []string
+	locale    string
+	discarded []string // ignored and filtered tokens, collected for DebugInfo
 }
 
 func (p properties) get(key string) string {
@@ -589,12 +1332,50 @@ func (p properties) getAny(keys ...string) (key, value string) {
 	return "", ""
 }
 
+// findLocale returns locale from app parameters or from locale token,
+// with underscore replaced by dash (fr_FR becomes fr-FR)
+func (p properties) findLocale() string {
+	locale := p.locale
+	if _, l := p.getAny("FBLC", "ByteFullLocale", "ByteLocale"); l != "" {
+		locale = l
+	}
+	return strings.Replace(locale, "_", "-", -1)
+}
+
+// findRegion returns upper case region from Region token sent by TikTok and
+// Alipay, or region subtag of the locale, like ES for es-ES or TW for zh-Hant-TW
+func (p properties) findRegion(locale string) string {
+	if r := p.get("Region"); isRegion(r) {
+		return strings.ToUpper(r)
+	}
+	if i := strings.LastIndexByte(locale, '-'); i > 0 && isRegion(locale[i+1:]) {
+		return strings.ToUpper(locale[i+1:])
+	}
+	return ""
+}
+
+// isRegion returns true for two letter country code, like PE,
+// or three digit UN M.49 region code, like 419 for Latin America
+func isRegion(s string) bool {
+	if len(s) != 2 && len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20 // lower case letters, digits are not changed
+		if len(s) == 2 && (c < 'a' || c > 'z') || len(s) == 3 && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 func (p properties) findMacOSVersion() string {
 	for _, token := range p.list {
-		if strings.Contains(token.Key, "OS") {
+		// version follows OS, like "arm64 Mac OS X 14.2.1" sent by Homebrew
+		if i := strings.Index(token.Key, "OS"); i != -1 {
 			if ver := findVersion(token.Value); ver != "" {
 				return ver
-			} else if ver = findVersion(token.Key); ver != "" {
+			} else if ver = findVersion(token.Key[i:]); ver != "" {
 				return ver
 			}
 		}
@@ -603,6 +1384,250 @@ func (p properties) findMacOSVersion() string {
 	return ""
 }
 
+// findAppleModelID returns Apple hardware identifier sent by apps,
+// like iPhone13,2 token or FBDV/iPhone13,2 sent by Facebook
+func (p properties) findAppleModelID() string {
+	for _, prop := range p.list {
+		if prop.Key == "FBDV" {
+			return prop.Value
+		}
+		for _, prefix := range []string{"iPhone", "iPad", "iPod", "Watch", "RealityDevice"} {
+			if len(prop.Key) > len(prefix) && strings.HasPrefix(prop.Key, prefix) &&
+				prop.Key[len(prefix)] >= '0' && prop.Key[len(prefix)] <= '9' && strings.IndexByte(prop.Key, ',') > 0 {
+				return prop.Key
+			}
+		}
+	}
+	return ""
+}
+
+// findPlayStation returns PlayStation device name, model and firmware
+// version from tokens like "PlayStation 4 5.55", "PLAYSTATION 3 4.81",
+// "PlayStation Vita 3.74" or "PlayStation 5/2.26"
+func (p properties) findPlayStation() (device, model, firmware string) {
+	const prefix = "playstation "
+	for _, prop := range p.list {
+		if !hasPrefixFold(prop.Key, prefix) {
+			continue
+		}
+		model, firmware = prop.Key[len(prefix):], prop.Value
+		if i := strings.IndexByte(model, ' '); i != -1 {
+			model, firmware = model[:i], model[i+1:]
+		}
+		return "PlayStation " + model, model, firmware
+	}
+	return "", "", ""
+}
+
+// findXbox returns Xbox model, like "Xbox One" or "Xbox Series X",
+// or just Xbox if model is not sent, like by Xbox 360
+func (p properties) findXbox() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "Xbox ") {
+			return prop.Key
+		}
+	}
+	return "Xbox"
+}
+
+// findNintendo returns Nintendo console name, like "Nintendo Switch",
+// "Nintendo WiiU" or "New Nintendo 3DS" from "New Nintendo 3DS like iPhone"
+func (p properties) findNintendo() string {
+	for _, prop := range p.list {
+		if !strings.HasPrefix(prop.Key, "Nintendo ") && !strings.HasPrefix(prop.Key, "New Nintendo ") {
+			continue
+		}
+		if i := strings.Index(prop.Key, " like "); i != -1 {
+			return prop.Key[:i]
+		}
+		return prop.Key
+	}
+	return ""
+}
+
+// findNintendoBrowser returns NintendoBrowser version, the token is
+// sent as "Mobile NintendoBrowser" by New 3DS
+func (p properties) findNintendoBrowser() string {
+	for _, prop := range p.list {
+		if prop.Key == "NintendoBrowser" || strings.HasSuffix(prop.Key, " NintendoBrowser") {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+// findQuest returns Meta Quest model token, like "Quest 2" or "Quest Pro"
+func (p properties) findQuest() string {
+	for _, prop := range p.list {
+		if prop.Key == "Quest" || strings.HasPrefix(prop.Key, "Quest ") {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// findNaverVersion returns Naver app version from its token group,
+// like "NAVER(inapp; search; 2000; 12.1.5)"
+func (p properties) findNaverVersion() string {
+	i, _ := p.getIndexValue("NAVER")
+	for _, prop := range p.list[i+1:] {
+		if prop.Value == "" && strings.IndexByte(prop.Key, '.') > 0 && findVersion(prop.Key) == prop.Key {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// findPrefixVersion returns version sent after the space in the token
+// with prefix, like "KAKAOTALK 10.4.5"
+func (p properties) findPrefixVersion(prefix string) string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, prefix) {
+			return prop.Key[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// findUbuntuTouch returns Ubuntu Touch version from "Ubuntu 16.04 like Android 9" token
+func (p properties) findUbuntuTouch() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "Ubuntu ") && strings.Contains(prop.Key, " like Android") {
+			return findVersion(prop.Key)
+		}
+	}
+	return ""
+}
+
+// findLine returns LINE app version from "Line/13.21.1/IAB" token, or from
+// "Safari Line/13.20.0" on iOS
+func (p properties) findLine() string {
+	return strings.TrimSuffix(p.getWord("Line"), "/IAB")
+}
+
+// getWord returns value of the token which is word or ends with the word,
+// since space separated tokens without version are joined with the next
+// one, like "V1_AND_SQ_8.9.75_4450_YYB_D QQ/8.9.75.12440"
+func (p properties) getWord(word string) string {
+	for _, prop := range p.list {
+		if isWord(prop.Key, word) {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+// existsWord returns true if token is word or ends with the word
+func (p properties) existsWord(word string) bool {
+	for _, prop := range p.list {
+		if isWord(prop.Key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWord(key, word string) bool {
+	return key == word || len(key) > len(word) && key[len(key)-len(word)-1] == ' ' && key[len(key)-len(word):] == word
+}
+
+// findEcosiaVersion returns Ecosia app version from the token like
+// "Ecosia ios@9.1.0.2189"
+func (p properties) findEcosiaVersion() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "Ecosia ") {
+			if i := strings.IndexByte(prop.Key, '@'); i != -1 {
+				return prop.Key[i+1:]
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// findWeiboVersion returns Weibo app version from the token sent after
+// Weibo, like "(iPhone14,2__weibo__13.8.1__iphone__os16.6)"
+func (p properties) findWeiboVersion() string {
+	i, _ := p.getIndexValue("Weibo")
+	if i+1 >= len(p.list) {
+		return ""
+	}
+	parts := strings.Split(p.list[i+1].Key, "__")
+	if len(parts) < 3 || parts[1] != "weibo" {
+		return ""
+	}
+	return parts[2]
+}
+
+// findRoku returns Roku SDK token, like Roku or Roku4640X
+func (p properties) findRoku() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "Roku") && strings.HasPrefix(prop.Value, "DVP-") {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// isVisionOS returns true if any of the Apple Vision Pro tokens is found,
+// visionOS was named xrOS in early SDKs
+func (p properties) isVisionOS() bool {
+	for _, prop := range p.list {
+		if prop.Key == "Apple Vision Pro" || strings.HasPrefix(prop.Key, "RealityDevice") ||
+			strings.Contains(prop.Key, VisionOS) || strings.Contains(prop.Key, "xrOS") {
+			return true
+		}
+	}
+	return false
+}
+
+// findVisionOSVersion returns version from "visionOS 1.0.1", "visionOS/1.0"
+// or "CPU visionOS 1_0 like Mac OS X" tokens
+func (p properties) findVisionOSVersion() string {
+	for _, prop := range p.list {
+		for _, os := range []string{VisionOS, "xrOS"} {
+			if i := strings.Index(prop.Key, os); i != -1 {
+				if v := findVersion(prop.Key[i+len(os):]); v != "" {
+					return v
+				}
+				return prop.Value
+			}
+		}
+	}
+	return ""
+}
+
+// findLinksVersion returns version of Links browser sent as the next token
+func (p properties) findLinksVersion() string {
+	i, _ := p.getIndexValue(Links)
+	if i >= 0 && i+1 < len(p.list) {
+		if v := p.list[i+1].Key; v != "" && v[0] >= '0' && v[0] <= '9' {
+			return v
+		}
+	}
+	return ""
+}
+
+// findPreferred returns the first of browser tokens sent with a version
+func (p properties) findPreferred(tokens []string) string {
+	for _, t := range tokens {
+		if p.get(t) != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// builtinBrowserToken returns true for browser tokens with builtin detection
+func builtinBrowserToken(token string) bool {
+	switch token {
+	case "EdgiOS", "EdgA", "Edg", Edge, "OPR", "OPX", "OPT", "OPiOS", "SamsungBrowser",
+		"HuaweiBrowser", Vivaldi, "Silk", "CriOS", "FxiOS", Firefox, Safari:
+		return true
+	}
+	return false
+}
+
 func (p properties) startsWith(value string) bool {
 	for _, prop := range p.list {
 		if strings.HasPrefix(prop.Key, value) {
@@ -626,47 +1651,199 @@ func (p properties) findInstagramVersion() string {
 	return ""
 }
 
+// findWindowsArch returns x64, arm64 or arm if architecture token is found
+func (p properties) findWindowsArch() string {
+	arch := ""
+	for _, prop := range p.list {
+		switch prop.Key {
+		case "Win64", "x64":
+			arch = "x64"
+		case "ARM64":
+			// Windows on ARM sends Win64 as well
+			return "arm64"
+		case "ARM":
+			// Windows RT
+			return "arm"
+		}
+	}
+	return arch
+}
+
+// findSurface returns Microsoft Surface device token, like Surface or Surface Hub
+func (p properties) findSurface() string {
+	for _, prop := range p.list {
+		if prop.Key == "Surface" || strings.HasPrefix(prop.Key, "Surface ") {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// findNokiaDevice returns Nokia device name from Series 40 and Series 60 tokens
+func (p properties) findNokiaDevice() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "Nokia") {
+			return prop.Key
+		}
+	}
+	return ""
+}
+
+// findEReader returns e-reader device name if any of the e-reader tokens
+// or already detected Android device name is found
+func (p properties) findEReader(device string) string {
+	if strings.HasPrefix(device, "BOOX") {
+		return "Onyx Boox"
+	}
+	for _, prop := range p.list {
+		switch {
+		case prop.Key == "Kindle":
+			return "Kindle"
+		case strings.HasPrefix(prop.Key, "Kobo"):
+			return "Kobo"
+		case prop.Key == "PocketBook":
+			return "PocketBook"
+		case prop.Key == "Onyx":
+			return "Onyx Boox"
+		}
+	}
+	return ""
+}
+
+// isIOSDesktopMode returns true if iOS only tokens are found,
+// like Mobile/15E148 or CriOS
+func (p properties) isIOSDesktopMode() bool {
+	if v := p.get(Mobile); v != "" && v[0] >= '0' && v[0] <= '9' {
+		return true
+	}
+	return p.existsAny("CriOS", "FxiOS", "EdgiOS", "OPiOS")
+}
+
+// electronApps maps tokens of well known Electron apps to app names
+var electronApps = map[string]string{
+	"Slack":    "Slack",
+	"discord":  "Discord",
+	"Teams":    "Microsoft Teams",
+	"Code":     "VS Code",
+	"Postman":  "Postman",
+	"obsidian": "Obsidian",
+}
+
+// findElectronApp returns name and version of the app, or the first token
+// with version if app is not well known
+func (p properties) findElectronApp() (name, version string) {
+	for _, prop := range p.list {
+		if app, ok := electronApps[prop.Key]; ok {
+			return app, prop.Value
+		}
+	}
+	for _, prop := range p.list {
+		if prop.Value != "" && prop.Key != "Electron" && !isFallbackExcluded(prop.Key) {
+			return prop.Key, prop.Value
+		}
+	}
+	return "", ""
+}
+
+// findEmbedded returns device name if any of the in-car browser
+// or smart appliance tokens is found
+func (p properties) findEmbedded() string {
+	for _, prop := range p.list {
+		switch prop.Key {
+		case "Tesla":
+			return "Tesla"
+		case "SMART-FRIDGE", "Family Hub":
+			return "Smart Fridge"
+		case "Printer":
+			return "Printer"
+		}
+	}
+	return ""
+}
+
+// findOperaEdition returns Opera GX or Opera Crypto if edition tokens
+// are found, otherwise Opera
+func (p properties) findOperaEdition() string {
+	for _, prop := range p.list {
+		switch {
+		case prop.Key == "OPX", prop.Key == "GX":
+			return OperaGX
+		case strings.HasPrefix(prop.Key, "Edition "):
+			if strings.Contains(prop.Key, "GX") {
+				return OperaGX
+			}
+			if strings.Contains(prop.Key, "Crypto") {
+				return OperaCrypto
+			}
+		}
+	}
+	return Opera
+}
+
 // findBestMatch from the rest of the bunch
 // in first cycle only return key with version value
 // if withVerValue is false, do another cycle and return any token
-func (p properties) findBestMatch(withVerOnly bool) string {
+func (p properties) findBestMatch(withVerOnly bool, f *Fallback) string {
 	n := 2
-	if withVerOnly {
+	if withVerOnly || f.RequireVersion {
 		n = 1
 	}
 	for i := 0; i < n; i++ {
-		for _, prop := range p.list {
-			switch prop.Key {
-			case Chrome, Firefox, Safari, Version, Mobile, MobileSafari, Mozilla, "AppleWebKit", WindowsNT, WindowsPhoneOS, Android, "Macintosh", Linux, "GSA", CrOS, Tablet, "OpenHarmony":
-			default:
-				// don't pick if starts with number
-				if len(prop.Key) != 0 && prop.Key[0] >= 48 && prop.Key[0] <= 57 {
-					break
-				}
-				if i == 0 {
-					if prop.Value != "" { // in first check, only return keys with value
-						return prop.Key
-					}
-				} else {
+		for j := range p.list {
+			prop := p.list[j]
+			if f.Last {
+				prop = p.list[len(p.list)-1-j]
+			}
+			if isFallbackExcluded(prop.Key) || f.excluded(prop.Key) {
+				continue
+			}
+			// don't pick if starts with number
+			if len(prop.Key) != 0 && prop.Key[0] >= 48 && prop.Key[0] <= 57 {
+				continue
+			}
+			if i == 0 {
+				if prop.Value != "" { // in first check, only return keys with value
 					return prop.Key
 				}
+			} else {
+				return prop.Key
 			}
 		}
 	}
 	return ""
 }
 
-var rxMacOSVer = regexp.MustCompile(`[_\d\.]+`)
+// isFallbackExcluded returns true for OS, engine and platform tokens
+// which are never picked as browser name
+func isFallbackExcluded(key string) bool {
+	switch key {
+	case Chrome, Firefox, Safari, Version, Mobile, MobileSafari, Mozilla, "AppleWebKit", WindowsNT, WindowsPhoneOS, Android, "Macintosh", Linux, "GSA", CrOS, Tablet, "OpenHarmony", "HarmonyOS", "Win64", "x64", "ARM64",
+		"KAIOS", KaiOS, "Series40", "Series60", "SymbianOS", Tizen, "Profile", "Configuration", "GNU", "Tesla":
+		return true
+	}
+	return false
+}
 
+// findVersion returns the first run of digits, dots and underscores in s,
+// with underscores replaced by dots, like 10.15.7 from "Intel Mac OS X 10_15_7"
 func findVersion(s string) string {
-	if ver := rxMacOSVer.FindString(s); ver != "" {
-		return strings.Replace(ver, "_", ".", -1)
+	start := strings.IndexAny(s, "0123456789._")
+	if start == -1 {
+		return ""
 	}
-	return ""
+	end := start + 1
+	for end < len(s) && isVersionChar(s[end]) {
+		end++
+	}
+	return strings.Replace(s[start:end], "_", ".", -1)
+}
+
+func isVersionChar(c byte) bool {
+	return '0' <= c && c <= '9' || c == '.' || c == '_'
 }
 
-// findAndroidDevice in tokens
-func (p *properties) findAndroidDevice(startIndex int) string {
+// findAndroidDevice in tokens, returns device name and build number if found
+func (p *properties) findAndroidDevice(startIndex int) (device, build string) {
 	for i := startIndex; i < startIndex+1; i++ {
 		if len(p.list) > i+1 {
 			dev := p.list[i+1].Key
@@ -675,17 +1852,58 @@ func (p *properties) findAndroidDevice(startIndex int) string {
 				continue
 			}
 			switch dev {
-			case Chrome, Firefox, Safari, OperaMini, "Presto", Version, Mobile, MobileSafari, Mozilla, "AppleWebKit", WindowsNT, WindowsPhoneOS, Android, "Macintosh", Linux, CrOS:
+			case Chrome, Firefox, Safari, OperaMini, "Presto", Version, Mobile, MobileSafari, Mozilla, "AppleWebKit", WindowsNT, WindowsPhoneOS, Android, "Macintosh", Linux, CrOS,
+				"ExoPlayerLib", "AndroidXMedia3":
 				// ignore these tokens, not device names
 			default:
-				if strings.Contains(strings.ToLower(dev), tablet) {
+				// build number is value of "<device> Build/<build>" token,
+				// or separate Build token in "<device>/<firmware> Build/<build>"
+				if strings.HasSuffix(dev, "Build") {
+					build = p.list[i+1].Value
+				} else if len(p.list) > i+2 && p.list[i+2].Key == "Build" {
+					build = p.list[i+2].Value
+				}
+				if containsFold(dev, tablet) {
 					p.list[i+1].Key = Tablet // leave Tablet tag for later table detection
 				} else {
 					p.list = append(p.list[:i+1], p.list[i+2:]...)
 				}
-				return strings.TrimSpace(strings.TrimSuffix(dev, "Build"))
+				return strings.TrimSpace(strings.TrimSuffix(dev, "Build")), build
 			}
 		}
 	}
+	return "", ""
+}
+
+// findHMSCore returns Huawei Mobile Services version from "HMSCore <version>" token
+func (p properties) findHMSCore() string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "HMSCore ") {
+			return prop.Key[len("HMSCore "):]
+		}
+	}
 	return ""
 }
+
+// containsFold reports whether substr is within s, ASCII case insensitive.
+// substr must be lower case.
+func containsFold(s, substr string) bool {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		j := 0
+		for j < n {
+			c := s[i+j]
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			if c != substr[j] {
+				break
+			}
+			j++
+		}
+		if j == n {
+			return true
+		}
+	}
+	return false
+}
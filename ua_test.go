@@ -1,230 +1,965 @@
 package useragent_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"reflect"
 	"strings"
 	"testing"
+	"unsafe"
 
 	ua "github.com/mileusna/useragent"
+	"github.com/mileusna/useragent/corpus"
 )
 
-var testTable = [][]string{
-	// useragent, name, version, mobile, os
-	// Mac
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8", ua.Safari, "10.1.2", "desktop", "macOS"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/60.0.3112.90 Safari/537.36", ua.Chrome, "60.0.3112.90", "desktop", "macOS"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.12; rv:54.0) Gecko/20100101 Firefox/54.0", ua.Firefox, "54.0", "desktop", "macOS"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36 OPR/46.0.2597.57", ua.Opera, "46.0.2597.57", "desktop", "macOS"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/60.0.3112.91 Safari/537.36 Vivaldi/1.92.917.39", "Vivaldi", "1.92.917.39", "desktop", "macOS"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.130 Safari/537.36 Edg/79.0.309.71", "Edge", "79.0.309.71", "desktop", "macOS"},
-
-	// Windows
-	{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", ua.Chrome, "59.0.3071.115", "desktop", "Windows"},
-	{"Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 6.1; WOW64; Trident/4.0; SLCC2; .NET CLR 2.0.50727; .NET CLR 3.5.30729; .NET CLR 3.0.30729; Media Center PC 6.0; .NET4.0C; .NET4.0E; InfoPath.2; GWX:RED)", ua.InternetExplorer, "8.0", "desktop", "Windows"},
-	{"Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1; SV1; .NET CLR 1.1.4322) NS8/0.9.6", ua.InternetExplorer, "6.0", "desktop", "Windows"},
-	{"Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/52.0.2743.116 Safari/537.36 Edge/15.15063", ua.Edge, "15.15063", "desktop", "Windows"},
-
-	// iPhone
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.Safari, "10.0", "mobile", "iOS", "iPhone"},
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.1.30 (KHTML, like Gecko) CriOS/60.0.3112.89 Mobile/14F89 Safari/602.1", ua.Chrome, "60.0.3112.89", "mobile", "iOS", "iPhone"},
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 9_3 like Mac OS X) AppleWebKit/601.1.46 (KHTML, like Gecko) OPiOS/14.0.0.104835 Mobile/13E233 Safari/9537.53", ua.Opera, "14.0.0.104835", "mobile", "iOS", "iPhone"},
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) FxiOS/8.1.1b4948 Mobile/14F89 Safari/603.2.4", ua.Firefox, "8.1.1b4948", "mobile", "iOS", "iPhone"},
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0 EdgiOS/44.11.15 Mobile/15E148 Safari/605.1.15", ua.Edge, "44.11.15", "mobile", "iOS", "iPhone"},
-
-	// iPad
-	{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.Safari, "10.0", "tablet", "iOS", "iPad"},
-	{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/602.1.50 (KHTML, like Gecko) CriOS/58.0.3029.113 Mobile/14F89 Safari/602.1", ua.Chrome, "58.0.3029.113", "tablet", "iOS", "iPad"},
-	{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) FxiOS/8.1.1b4948 Mobile/14F89 Safari/603.2.4", ua.Firefox, "8.1.1b4948", "tablet", "iOS", "iPad"},
-
-	// Android Tablet
-	{"Mozilla/5.0 (Android 4.4; Tablet; rv:41.0) Gecko/41.0 Firefox/41.0", ua.Firefox, "41.0", "tablet", "Android", "Tablet"},
-	{"Mozilla/5.0 (Linux; Android 9; Chrome tablet) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/110.0.0.0 Mobile Safari/537.36", ua.Chrome, "110.0.0.0", "tablet", "Android", "Chrome tablet"},
-
-	// Android
-	{"Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.125 Mobile Safari/537.36", ua.Chrome, "59.0.3071.125", "mobile", "Android", "GT-I9300"},
-	{"Mozilla/5.0 (Android 4.3; Mobile; rv:54.0) Gecko/54.0 Firefox/54.0", ua.Firefox, "54.0", "mobile", "Android"},
-	{"Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/55.0.2883.91 Mobile Safari/537.36 OPR/42.9.2246.119956", ua.Opera, "42.9.2246.119956", "mobile", ua.Android},
-	{"Opera/9.80 (Android; Opera Mini/28.0.2254/66.318; U; en) Presto/2.12.423 Version/12.16", ua.OperaMini, "28.0.2254/66.318", "mobile", "Android", ""},
-	{"Mozilla/5.0 (Linux; U; Android 4.3; en-us; GT-I9300 Build/JSS15J) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "Android browser", "4.0", "mobile", "Android"},
-	{"Mozilla/5.0 (Linux; Android 10; ONEPLUS A6003) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/73.0.3683.0 Mobile Safari/537.36 EdgA/44.11.4.4140", ua.Edge, "44.11.4.4140", "mobile", "Android", "ONEPLUS A6003"},
-
-	{"Mozilla/5.0 (Linux; Android 6.0.1; SAMSUNG SM-A310F/A310FXXU2BQB1 Build/MMB29K) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/5.4 Chrome/51.0.2704.106 Mobile Safari/537.36", "Samsung Browser", "5.4", "mobile", "Android", "SAMSUNG SM-A310F"},
-	{"Mozilla/5.0 (Linux; Android 9; LM-Q630) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.198 Mobile Safari/537.36", ua.Chrome, "86.0.4240.198", "mobile", "Android", "LM-Q630"},
-	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/534.24 (KHTML, like Gecko) Chrome/79.0.3945.147 Safari/534.24 XiaoMi/MiuiBrowser/12.11.5-gn", "Miui Browser", "12.11.5-gn", "mobile", ua.Linux},
-	{"Mozilla/5.0 (Linux; U; Android 11; ru-ru; Redmi Note 10S Build/RP1A.200720.011) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.116 Mobile Safari/537.36 XiaoMi/MiuiBrowser/12.13.2-gn", "Miui Browser", "12.13.2-gn", "mobile", ua.Android, "Redmi Note 10S"},
-
-	{"Mozilla/5.0 (Linux; Android 10; MED-LX9N; HMSCore 6.6.0.311) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/92.0.4515.105 HuaweiBrowser/12.1.0.303 Mobile Safari/537.36", "Huawei Browser", "12.1.0.303", "mobile", "Android"},
-	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/22.0 Chrome/111.0.5563.116 Safari/537.36", ua.SamsungBrowser, "22.0", "mobile", ua.Android},
-
-	// useragent, name, version, mobile, os
-	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/71.0.3578.99 Mobile Safari/537.36", ua.Chrome, "71.0.3578.99", "mobile", ua.Android},
-	{"Mozilla/5.0 (Android 9; Mobile; rv:64.0) Gecko/64.0 Firefox/64.0", ua.Firefox, "64.0", "mobile", ua.Android},
-	{"Opera/9.80 (Android; Opera Mini/38.0.2254/128.54; U; en) Presto/2.12.423 Version/12.16", ua.OperaMini, "38.0.2254/128.54", "mobile", ua.Android},
-	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.110 Mobile Safari/537.36 OPR/49.2.2361.134358", ua.Opera, "49.2.2361.134358", "mobile", ua.Android},
-	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.86 Mobile Safari/537.36 EdgA/42.0.92.2864", ua.Edge, "42.0.92.2864", "mobile", ua.Android},
-	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/71.0.3578.99 Mobile Safari/537.36 OPT/1.14.51", ua.OperaTouch, "1.14.51", "mobile", ua.Android},
-	{"Mozilla/5.0 (Linux; Android 7.0; Moto G (4)) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Mobile Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "mobile", ua.Android, "Moto G"},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.88 Safari/537.36", ua.Chrome, "87.0.4280.88", "desktop", ua.MacOS}, // Lighthouse
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "desktop", ua.MacOS},
-	{"Mozilla/5.0 (Linux; Android 7.0; Moto G (4)) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Mobile Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "mobile", ua.Android},
-
-	// Windows phone
-	{"Mozilla/4.0 (compatible; MSIE 7.0; Windows Phone OS 7.0; Trident/3.1; IEMobile/7.0; NOKIA; Lumia 630)", ua.InternetExplorer, "7.0", "mobile", ua.WindowsPhone},
-
-	// FreeBSD
-	{"Mozilla/5.0 (compatible; Konqueror/4.5; FreeBSD) KHTML/4.5.4 (like Gecko)", "Konqueror", "4.5", "desktop", "FreeBSD"},
-
-	// Bots
-	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1", "mobile", "Android", "Nexus 5X"},
-	{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1", "bot", ""},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.1 Safari/605.1.15 (Applebot/0.1; +http://www.apple.com/go/applebot)", "Applebot", "0.1", "bot", ""},
-	{"Twitterbot/1.0", ua.Twitterbot, "1.0", ua.Applebot, ""},
-	{"facebookexternalhit/1.1", ua.FacebookExternalHit, "1.1", "bot", ""},
-	{"facebookcatalog/1.0", "facebookcatalog", "1.0", "bot", ""},
-	{"Mozilla/5.0 (compatible; SemrushBot/7~bl; +http://www.semrush.com/bot.html", "SemrushBot", "7~bl", "bot", ""},
-	{"Mozilla/5.0 (compatible; YandexBot/3.0; +http://yandex.com/bots) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/81.0.4044.268", "YandexBot", "3.0", "bot", ""},
-	{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", "Discordbot", "2.0", "bot", ""},
-	{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "Bingbot", "2.0", "bot", ""},                                                                                                                                    // old binbot
-	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm) Chrome/100.0.0.0 Safari/537.36", "Bingbot", "2.0", "bot", ""},                                                               // new bingbot desktop
-	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/100.1.0.0 Mobile Safari/537.36 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "Bingbot", "2.0", "bot", ua.Android}, // new bingbot mobile
-	{"Mozilla/5.0 (compatible; Yahoo Ad monitoring; https://help.yahoo.com/kb/yahoo-ad-monitoring-SLN24857.html)  tands-prod-eng.hlfs-prod---sieve.hlfs-desktop/1681336006-0", "Yahoo Ad monitoring", "", "bot", ""},
-	{"Mozilla/5.0 (compatible; Yahoo Ad monitoring; https://help.yahoo.com/kb/yahoo-ad-monitoring-SLN24857.html) cnv.aws-prod---sieve.hlfs-rest_client/1681346790-0", "Yahoo Ad monitoring", "", "bot", ""},
-	{"GoogleProber", "GoogleProber", "", "bot", ""},
-	{"GoogleProducer; (+http://goo.gl/7y4SX)", "GoogleProducer", "", "bot", ""},
-	{"Mozilla/5.0 (compatible; Bytespider; spider-feedback@bytedance.com) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.0.0 Safari/537.36", "Bytespider", "", "bot", ""},
-	{"Mozilla/5.0 (Linux; Android 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36 (compatible; Bytespider; spider-feedback@bytedance.com)", "Bytespider", "", "bot", ua.Android},
-
-	// Google ads bots
-	{"Mozilla/5.0 (Linux; Android 4.0.0; Galaxy Nexus Build/IMM76B) AppleWebKit/537.36 (KHTML, like Gecko; Mediapartners-Google) Chrome/104.0.0.0 Mobile Safari/537.36", ua.GoogleAdsBot, "", "bot", ua.Android},
-	{"Mozilla/5.0 (Linux; Android 5.0; SM-G920A) AppleWebKit (KHTML, like Gecko) Chrome Mobile Safari (compatible; AdsBot-Google-Mobile; +http://www.google.com/mobile/adsbot.html)", ua.GoogleAdsBot, "", "bot", ua.Android},
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 14_7_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.2 Mobile/15E148 Safari/604.1 (compatible; AdsBot-Google-Mobile; +http://www.google.com/mobile/adsbot.html)", ua.GoogleAdsBot, "", "bot", ua.IOS},
-	{"Mozilla/5.0 (iPhone; U; CPU iPhone OS 10_0 like Mac OS X; en-us) AppleWebKit/602.1.38 (KHTML, like Gecko) Version/10.0 Mobile/14A5297c Safari/602.1 (compatible; Mediapartners-Google/2.1; +http://www.google.com/bot.html)", ua.GoogleAdsBot, "", "bot", ua.IOS},
-	// Brave
-	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Brave Chrome/87.0.4280.101 Safari/537.36", ua.Chrome, "87.0.4280.101", "desktop", ua.Linux},
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.141 Safari/537.36", ua.Chrome, "87.0.4280.141", "desktop", ua.MacOS},
-
-	// HeadlessChrome
-	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/98.0.4758.0 Safari/537.36", ua.HeadlessChrome, "98.0.4758.0", "desktop", ua.Linux},
-
-	//FB App
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/19E258 [FBAN/FBIOS;FBDV/iPhone8,2;FBMD/iPhone;FBSN/iOS;FBSV/15.4.1;FBSS/3;FBID/phone;FBLC/fr_FR;FBOP/5]", ua.FacebookApp, "FBIOS", "mobile", ua.IOS},
-	{"Mozilla/5.0 (Linux; Android 13; SM-T220 Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/109.0.5414.117 Safari/537.36 [FB_IAB/FB4A;FBAV/400.0.0.37.76;]", ua.FacebookApp, "400.0.0.37.76", "", ua.Android},
-
-	//Instagram
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 270.0.0.13.83 (iPhone13,2; iOS 16_3; es_ES; es-ES; scale=3.00; 1170x2532; 445843881) NW/1", ua.InstagramApp, "270.0.0.13.83", "mobile", ua.IOS},
-
-	// Tiktok
-	{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_5 like Mac OS ) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 musical_ly_28.2.0 JsSdk/2.0 NetType/WIFI Channel/App Store ByteLocale/es Region/PE RevealType/Dialog isDarkMode/0 WKWebView/1 BytedanceWebview/d8a21c6 FalconTag/D6EBBF89-6D75-4BBD-9304-BF199C6B4DB1", ua.TiktokApp, "", "mobile", ua.IOS},
-	{"Mozilla/5.0 (Linux; Android 10; AGS3K-W09 Build/HUAWEIAGS3K-W09; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/88.0.4324.93 Safari/537.36 trill_2022803040 JsSdk/1.0 NetType/WIFI Channel/huaweiadsglobal_int AppName/musical_ly app_version/28.3.4 ByteLocale/es ByteFullLocale/es Region/PE BytedanceWebview/d8a21c6", ua.TiktokApp, "28.3.4", ua.Android},
-
-	// other
-	{"Mozilla/5.0 (X11; CrOS x86_64 14150.74.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.114 Safari/537.36", ua.Chrome, "94.0.4606.114", "desktop", ua.ChromeOS},
-	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/56.0.2924.87 Safari/537.36 Google (+https://developers.google.com/+/web/snippet/)", ua.Chrome, "56.0.2924.87", "bot", ua.Linux}, // Google+ fetch
-
-	// tools
-	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_4) AppleWebKit/537.36 (KHTML, like Gecko) QtWebEngine/5.6.0 Chrome/45.0.2454.101 Safari/537.36", "QtWebEngine", "5.6.0", "", "macOS"},
-	{"Go-http-client/1.1", "Go-http-client", "1.1", "", ""},
-	{"Wget/1.12 (linux-gnu)", "Wget", "1.12", "", ""},
-	{"Wget/1.17.1 (darwin15.2.0)", "Wget", "1.17.1", "", ""},
-	{"Seafile/9.0.2 (Linux)", "Seafile", "9.0.2", "", "Linux"},
-
-	// unstandard stuff
-	{"BUbiNG (+http://law.di.unimi.it/BUbiNG.html)", "BUbiNG", "", "", ""},
-	//{"Aweme 8.2.0 rv:82017 (iPhone6,2; iOS 12.4; zh_CN) Cronet", "Aweme", "", "", ""},
-	{"surveyon/3.1.0 Mobile (Android: 6.0.1; MODEL:SM-G532G; PRODUCT:grandppltedx; MANUFACTURER:samsung;)", "surveyon", "3.1.0", "mobile", ua.Android},
-	{"surveyon/3.1.0 Mobile (Android: 9; MODEL:CPH1923; PRODUCT:CPH1923; MANUFACTURER:OPPO;)", "surveyon", "3.1.0", "mobile", ua.Android},
-	{"surveyon/3.1.0 Mobile (Android: 13; MODEL:SM-M127F; PRODUCT:m12nnxx; MANUFACTURER:samsung;)", "surveyon", "3.1.0", "mobile", ua.Android},
-	{"surveyon/2.9.5 (iPhone; CPU iPhone OS 12_5_7 like Mac OS X)", "surveyon", "2.9.5", "mobile", ua.IOS},
-	{"Mozilla/5.0 (BlackBerry; U; BlackBerry 9900; en-US) AppleWebKit/534.11+ (KHTML, like Gecko) Version/7.0.0.187 Mobile Safari/534.11+", "BlackBerry", "7.0.0.187", "mobile", "BlackBerry"},
-	{"Mozilla/5.0 (X11; CrOS armv7l 13099.110.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4147.136 Safari/537.36", ua.Chrome, "84.0.4147.136", "desktop", ua.ChromeOS},
-	{"SonyEricssonK310iv/R4DA Browser/NetFront/3.3 Profile/MIDP-2.0 Configuration/CLDC-1.1 UP.Link/6.3.1.13.0", "NetFront", "3.3", "mobile", ""},
-
-	// Device names
-	{"Mozilla/5.0 (Linux; Android 10; 8092) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36", "Chrome", "112.0.0.0", "mobile", ua.Android, "8092"},
-	{"Mozilla/5.0 (Linux; Android 10) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/96.0.4664.54 Mobile DuckDuckGo/5 Safari/537.36", "Mobile DuckDuckGo", "5", "mobile", ua.Android, ""},
-	{"Mozilla/5.0 (Linux; Android 6.0; VIVAX TABLET TPC-101 3G) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/106.0.0.0 Safari/537.36", ua.Chrome, "106.0.0.0", "tablet", ua.Android, "VIVAX TABLET TPC-101 3G"},
-	{"Mozilla/5.0 (Linux; Android 8.1.0; 8068 Build/O11019) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/111.0.5563.116 Safari/537.36", ua.Chrome, "111.0.5563.116", "mobile", ua.Android, "8068"},
-	{"Mozilla/5.0 (Linux; Android 8.1.0; Lenovo TB-7104F Build/O11019) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.5304.91 Safari/537.36", ua.Chrome, "107.0.5304.91", "mobile", ua.Android, "Lenovo TB-7104F"},
-	{"Mozilla/5.0 (Linux; Android 7.1.1; Lenovo TB-X304L Build/NMF26F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/56.0.2924.87 Safari/537.36", ua.Chrome, "56.0.2924.87", "mobile", ua.Android, "Lenovo TB-X304L"},
-	{"Mozilla/5.0 (Linux; Android 4.4.4; SM-T560 Build/KTU84P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/68.0.3440.91 Safari/537.36", ua.Chrome, "68.0.3440.91", "mobile", ua.Android, "SM-T560"},
-	{"Mozilla/5.0 (Linux; Android 5.1; B3-A20 Build/LMY47I) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/50.0.2661.89 Safari/537.36", ua.Chrome, "50.0.2661.89", "mobile", ua.Android, "B3-A20"},
-	{"Mozilla/5.0 (Linux; Android 11; TPC_8074G Build/RP1A.200720.011) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/105.0.5195.136 Safari/537.36", ua.Chrome, "105.0.5195.136", "mobile", ua.Android, "TPC_8074G"},
-	{"Mozilla/5.0 (Linux; Android 9; m5621 Build/PPR2.180905.006.A1; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/66.0.3359.158 Safari/537.36", ua.Chrome, "66.0.3359.158", "mobile", ua.Android, "m5621"},
-	{"Mozilla/5.0 (Linux; Android 10; meanIT_X20 Build/QP1A.190711.020) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/110.0.5481.153 Safari/537.36", ua.Chrome, "110.0.5481.153", "mobile", ua.Android, "meanIT_X20"},
-	{"Mozilla/5.0 (Linux; Android 10;)", "Mozilla/5.0 (Linux; Android 10;)", "", "mobile", ua.Android},
-	// {`() { ignored; }; echo Content-Type: text/plain ; echo ; echo "bash_cve_2014_6271_rce Output : $((70+91))"`, "", "mobile", ua.Android},
-	//{`${jndi:ldap://log4shell-generic-8ZnJfq2XFL3GWyaLyOpT${lower:ten}.w.nessus.org/nessus}`, "", "mobile", ua.Android},
-	//
-
-	{"Mozilla/5.0 (Phone; OpenHarmony 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36  ArkWeb/4.1.6.1 Mobile", "ArkWeb", "4.1.6.1", "mobile", ua.Harmony, ""},
-
-	//
-	// ${jndi:ldap://log4shell-generic-8ZnJfq2XFL3GWyaLyOpT${lower:ten}.w.nessus.org/nessus}
-
-	// TODO:
-	// Mozilla/5.0 (Linux; U; Android 13; sr-rs; V2206 Build/TP1A.220624.014) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/61.0.3163.128 Mobile Safari/537.36 XiaoMi/Mint Browser/3.9.3
-	// Mozilla/5.0 (Linux; U; Android 12; sr-rs; 2201116SG Build/SKQ1.211006.001) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/61.0.3163.128 Mobile Safari/537.36 XiaoMi/Mint Browser/3.9.3
-	// Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/111.0.0.0 Safari/537.36 Config/92.2.3471.72
-	// Mozilla/5.0 (iPhone; CPU iPhone OS 15_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148
-	// Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko)
-	// Mozilla/5.0 (iPhone; CPU iPhone OS 15_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148
-	// Mozilla/5.0 (iPad; CPU OS 10_3_3 like Mac OS X) AppleWebKit/603.3.8 (KHTML, like Gecko) Mobile/14G60
-
-	//GooglePlus   "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/56.0.2924.87 Safari/537.36 Google (+https://developers.google.com/+/web/snippet/)"
-	//Mozilla/5.0 (Macintosh; Intel Mac OS X 10_10_1) AppleWebKit/600.2.5 (KHTML, like Gecko) Version/8.0.2 Safari/600.2.5 (Applebot/0.1; +http://www.apple.com/go/applebot)
-	//Mozilla/5.0 (Macintosh; Intel Mac OS Xt 10_11_4) AppleWebKit/537.36 (KHTML, like Gecko) QtWebEngine/5.6.0 Chrome/45.0.2454.101 Safari/537.36
-
+// loadCorpus returns entries of the corpus package
+func loadCorpus(t testing.TB) []ua.CorpusEntry {
+	var entries []ua.CorpusEntry
+	for _, e := range corpus.Entries() {
+		entries = append(entries, e.CorpusEntry)
+	}
+	return entries
 }
 
 func TestParse(t *testing.T) {
-	for _, test := range testTable {
-		ua := ua.Parse(test[0])
-		if ua.Name != test[1] {
-			t.Error("\n", test[0], "\nName should be", test[1], "not", ua.Name)
-			fmt.Printf("%+v", ua)
+	for _, test := range loadCorpus(t) {
+		agent := ua.Parse(test.UserAgent)
+		for _, diff := range test.Check(agent) {
+			t.Error("\n", test.UserAgent, "\n", diff)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		ua, name, arch string
+	}{
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", "Windows 7", ""},
+		{"Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1; SV1; .NET CLR 1.1.4322) NS8/0.9.6", "Windows XP", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Windows 10/11", "x64"},
+		{"Mozilla/5.0 (Windows NT 10.0; ARM64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Windows 10/11", "arm64"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.OSVersionName() != test.name {
+			t.Error("\n", test.ua, "\nOSVersionName should be", test.name, "not", agent.OSVersionName())
 		}
-		if ua.Version != test[2] {
-			t.Error("\nVersion should be", test[2], "not", ua.Version)
+		if agent.Arch != test.arch {
+			t.Error("\n", test.ua, "\nArch should be", test.arch, "not", agent.Arch)
 		}
+	}
+}
 
-		if len(test) > 3 {
-			if test[3] == "desktop" && ua.Mobile {
-				t.Error("\n", ua.String, "should be desktop type not mobile")
-			}
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		ua, locale string
+	}{
+		{"Mozilla/5.0 (Linux; U; Android 4.3; en-us; GT-I9300 Build/JSS15J) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "en-us"},
+		{"Mozilla/5.0 (Linux; U; Android 11; ru-ru; Redmi Note 10S Build/RP1A.200720.011) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.116 Mobile Safari/537.36 XiaoMi/MiuiBrowser/12.13.2-gn", "ru-ru"},
+		{"Opera/9.80 (Android; Opera Mini/28.0.2254/66.318; U; en) Presto/2.12.423 Version/12.16", "en"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/19E258 [FBAN/FBIOS;FBDV/iPhone8,2;FBMD/iPhone;FBSN/iOS;FBSV/15.4.1;FBSS/3;FBID/phone;FBLC/fr_FR;FBOP/5]", "fr-FR"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 270.0.0.13.83 (iPhone13,2; iOS 16_3; es_ES; es-ES; scale=3.00; 1170x2532; 445843881) NW/1", "es-ES"},
+		{"Mozilla/5.0 (Linux; Android 10; AGS3K-W09 Build/HUAWEIAGS3K-W09; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/88.0.4324.93 Safari/537.36 trill_2022803040 JsSdk/1.0 NetType/WIFI Channel/huaweiadsglobal_int AppName/musical_ly app_version/28.3.4 ByteLocale/es ByteFullLocale/es Region/PE BytedanceWebview/d8a21c6", "es"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ""},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.Locale != test.locale {
+			t.Error("\n", test.ua, "\nLocale should be", test.locale, "not", agent.Locale)
+		}
+	}
+}
+
+func TestRegion(t *testing.T) {
+	tests := []struct {
+		ua, region string
+	}{
+		{"Mozilla/5.0 (Linux; Android 10; AGS3K-W09 Build/HUAWEIAGS3K-W09; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/88.0.4324.93 Safari/537.36 trill_2022803040 JsSdk/1.0 NetType/WIFI Channel/huaweiadsglobal_int AppName/musical_ly app_version/28.3.4 ByteLocale/es ByteFullLocale/es Region/PE BytedanceWebview/d8a21c6", "PE"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/20G75 Ariver/1.1.0 AliApp(AP/10.5.20.6000) Nebula WK RVKType(0) AlipayDefined(nt:WIFI,ws:390|780|3.0) AlipayClient/10.5.20.6000 Language/zh-Hans Region/CN", "CN"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 270.0.0.13.83 (iPhone13,2; iOS 16_3; es_ES; es-ES; scale=3.00; 1170x2532; 445843881) NW/1", "ES"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/19E258 [FBAN/FBIOS;FBDV/iPhone8,2;FBMD/iPhone;FBSN/iOS;FBSV/15.4.1;FBSS/3;FBID/phone;FBLC/fr_FR;FBOP/5]", "FR"},
+		{"Mozilla/5.0 (Linux; Android 12; SM-G991B Build/SP1A.210812.016; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.144 Mobile Safari/537.36 trill_320203 JsSdk/1.0 NetType/WIFI AppName/trill app_version/32.2.3 ByteLocale/zh-Hant ByteFullLocale/zh-Hant-TW", "TW"},
+		{"Mozilla/5.0 (Linux; U; Android 4.3; en-us; GT-I9300 Build/JSS15J) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "US"},
+		{"Opera/9.80 (Android; Opera Mini/28.0.2254/66.318; U; en) Presto/2.12.423 Version/12.16", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ""},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.Region != test.region {
+			t.Error("\n", test.ua, "\nRegion should be", test.region, "not", agent.Region)
+		}
+	}
+}
+
+func TestOSDistro(t *testing.T) {
+	tests := []struct {
+		ua, name, distro, version string
+	}{
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0", ua.Firefox, "Ubuntu", ""},
+		{"Mozilla/5.0 (X11; U; Linux i686; en-US; rv:1.9.2.3) Gecko/20100423 Ubuntu/10.04 (lucid) Firefox/3.6.3", ua.Firefox, "Ubuntu", "10.04"},
+		{"Mozilla/5.0 (X11; U; Linux x86_64; en-US; rv:1.9.2.3) Gecko/20100403 Fedora/3.6.3-4.fc13 Firefox/3.6.3", ua.Firefox, "Fedora", ""},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Ubuntu Chromium/37.0.2062.94 Chrome/37.0.2062.94 Safari/537.36", "Chromium", "Ubuntu", ""},
+		{"Mozilla/5.0 (X11; U; Linux i686; en-US) AppleWebKit/534.16 (KHTML, like Gecko) Ubuntu/10.10 Chromium/10.0.648.0 Chrome/10.0.648.0 Safari/534.16", "Chromium", "Ubuntu", "10.10"},
+		{"Mozilla/5.0 (X11; Linux x86_64; Ubuntu 22.04) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "Ubuntu", "22.04"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0 Arch Linux", ua.Firefox, "Arch Linux", ""},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "", ""},
+		{"Playwright/1.40.0 (x64; ubuntu 22.04) node/18.19", "Playwright", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.OSDistro != test.distro || agent.OSDistroVersion != test.version {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.distro, test.version, "not", agent.Name, agent.OSDistro, agent.OSDistroVersion)
+		}
+	}
+}
+
+func TestAndroidBuild(t *testing.T) {
+	tests := []struct {
+		ua, build, hms string
+		webView        bool
+	}{
+		{"Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.125 Mobile Safari/537.36", "JSS15J", "", false},
+		{"Mozilla/5.0 (Linux; Android 6.0.1; SAMSUNG SM-A310F/A310FXXU2BQB1 Build/MMB29K) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/5.4 Chrome/51.0.2704.106 Mobile Safari/537.36", "MMB29K", "", false},
+		{"Mozilla/5.0 (Linux; Android 9; m5621 Build/PPR2.180905.006.A1; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/66.0.3359.158 Safari/537.36", "PPR2.180905.006.A1", "", true},
+		{"Mozilla/5.0 (Linux; Android 10; MED-LX9N; HMSCore 6.6.0.311) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/92.0.4515.105 HuaweiBrowser/12.1.0.303 Mobile Safari/537.36", "", "6.6.0.311", false},
+		{"Mozilla/5.0 (Linux; Android 9; LM-Q630) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.198 Mobile Safari/537.36", "", "", false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.AndroidBuild != test.build {
+			t.Error("\n", test.ua, "\nAndroidBuild should be", test.build, "not", agent.AndroidBuild)
+		}
+		if agent.HMSCore != test.hms {
+			t.Error("\n", test.ua, "\nHMSCore should be", test.hms, "not", agent.HMSCore)
+		}
+		if agent.WebView != test.webView {
+			t.Error("\n", test.ua, "\nWebView should be", test.webView)
+		}
+	}
+}
 
-			if test[3] == "mobile" && !ua.Mobile {
-				t.Error("\n", ua.String, "should be mobile")
-				fmt.Printf("%+v", ua)
-			}
-			if test[3] == "tablet" && !ua.Tablet {
-				t.Error("\n", ua.String, "should be tablet")
-				fmt.Printf("%+v", ua)
-			}
-			if test[3] == "bot" && !ua.Bot {
-				t.Error("\n", ua.String, "should be bot")
-				fmt.Printf("%+v", ua)
-			}
+func TestBotReason(t *testing.T) {
+	tests := []struct {
+		ua     string
+		bot    bool
+		reason string
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", true, ua.BotReasonKnown},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", true, ua.BotReasonKeyword},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/56.0.2924.87 Safari/537.36 Google (+https://developers.google.com/+/web/snippet/)", true, ua.BotReasonURL},
+		{"Mozilla/5.0 (compatible; LinkChecker/1.0; +https://example.com/crawler.html)", true, ua.BotReasonURL},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_4) AppleWebKit/537.36 (KHTML, like Gecko) QtWebEngine/5.6.0 Chrome/45.0.2454.101 Safari/537.36 (+https://www.example.com/app)", false, ""},
+		{"BUbiNG (+http://law.di.unimi.it/BUbiNG.html)", true, ua.BotReasonURL},
+		{"MyApp/1.0 (https://www.example.com/app)", false, ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Bot != test.bot || agent.BotReason != test.reason {
+			t.Error("\n", test.ua, "\nBot should be", test.bot, test.reason, "not", agent.Bot, agent.BotReason)
 		}
+	}
+}
 
-		if len(test) > 4 && test[4] != ua.OS {
-			t.Error("\n", test[0], "OS should be", test[4], "not", ua.OS)
+func TestGooglebotType(t *testing.T) {
+	tests := []struct {
+		ua, typ, device, vendor, engine string
+	}{
+		{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.6478.126 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.GooglebotSmartphone, "Nexus 5X", "Google", ua.Blink},
+		{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/W.X.Y.Z Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.GooglebotSmartphone, "Nexus 5X", "Google", ua.Blink},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/AP1A.240305.019) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/999.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.GooglebotSmartphone, "Pixel 8", "Google", ua.Blink},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/W.X.Y.Z Safari/537.36", ua.GooglebotDesktop, "", "", ua.Blink},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.GooglebotDesktop, "", "", ""},
+		{"Googlebot-Image/1.0", "", "", "", ""},
+		{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "", "", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if !agent.Bot && test.typ != "" {
+			t.Error("\n", test.ua, "\nshould be bot")
+		}
+		if agent.GooglebotType() != test.typ || agent.Device != test.device || agent.DeviceVendor != test.vendor || agent.Engine != test.engine {
+			t.Error("\n", test.ua, "\nGooglebot should be", test.typ, test.device, test.vendor, test.engine,
+				"not", agent.GooglebotType(), agent.Device, agent.DeviceVendor, agent.Engine)
 		}
+	}
+}
 
-		if len(test) > 5 && test[5] != ua.Device {
-			t.Error("\n", test[0], "Device should be", test[5], "not", ua.Device)
+func TestAppleBots(t *testing.T) {
+	tests := []struct {
+		ua, name, category string
+		mobile             bool
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.1 Safari/605.1.15 (Applebot/0.1; +http://www.apple.com/go/applebot)", ua.Applebot, "", false},
+		{"Mozilla/5.0 (compatible; Applebot-Extended/0.1; +http://www.apple.com/go/applebot)", ua.ApplebotExtended, ua.CategoryAICrawler, false},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 12_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.0 Mobile/15E148 Safari/604.1 AppleNewsBot", ua.AppleNewsBot, "", true},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || !agent.Bot || agent.Category != test.category || agent.Mobile != test.mobile {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.category, test.mobile, "not", agent.Name, agent.Bot, agent.Category, agent.Mobile)
 		}
+	}
 
-		// fmt.Println(ua.Version, "==>", ua.VersionNoShort())
+	if crawlers := ua.AICrawlers(); !reflect.DeepEqual(crawlers, []string{ua.ApplebotExtended}) {
+		t.Error("AICrawlers should be Applebot-Extended, not", crawlers)
+	}
+}
 
+func TestBotContact(t *testing.T) {
+	tests := []struct {
+		ua      string
+		contact string
+		url     string
+	}{
+		{"Mozilla/5.0 (Linux; Android 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36 (compatible; Bytespider; spider-feedback@bytedance.com)", "spider-feedback@bytedance.com", ""},
+		{"ia_archiver (+http://www.alexa.com/site/help/webmasters; crawler@alexa.com)", "crawler@alexa.com", "http://www.alexa.com/site/help/webmasters"},
+		{"MyBot/1.0 (mailto:ops@example.com)", "ops@example.com", ""},
+		{"ResearchCrawler/2.1 (contact: research-team@uni.example.edu; +https://uni.example.edu/crawler)", "research-team@uni.example.edu", "https://uni.example.edu/crawler"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "", "http://www.google.com/bot.html"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1 (Ecosia ios@9.1.0.2189)", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.BotContact != test.contact || agent.URL != test.url {
+			t.Error("\n", test.ua, "\nBotContact and URL should be", test.contact, test.url, "not", agent.BotContact, agent.URL)
+		}
+	}
+}
+
+func TestURLs(t *testing.T) {
+	tests := []struct {
+		ua   string
+		name string
+		urls []string
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "Googlebot", []string{"http://www.google.com/bot.html"}},
+		{"Mozilla/5.0 (compatible; ExampleBot/1.2; +https://example.com/bot.html; https://example.com/contact)", "ExampleBot", []string{"https://example.com/bot.html", "https://example.com/contact"}},
+		{"ExampleBot/1.2 (see http://example.com/bot.html) (+https://example.com/contact)", "ExampleBot", []string{"http://example.com/bot.html", "https://example.com/contact"}},
+		{"ExampleCrawler http://example.com/crawler SiteCheck/3.1", "SiteCheck", []string{"http://example.com/crawler"}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome", nil},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || !reflect.DeepEqual(agent.URLs, test.urls) {
+			t.Error("\n", test.ua, "\nName and URLs should be", test.name, test.urls, "not", agent.Name, agent.URLs)
+		}
+		if len(test.urls) > 0 && agent.URL != test.urls[0] {
+			t.Error("\n", test.ua, "\nURL should be", test.urls[0], "not", agent.URL)
+		}
+	}
+}
+
+func TestDeviceType(t *testing.T) {
+	tests := []struct {
+		ua         string
+		deviceType ua.DeviceType
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.DevicePhone},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.DeviceTablet},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/534.24 (KHTML, like Gecko) Chrome/79.0.3945.147 Safari/534.24 XiaoMi/MiuiBrowser/12.11.5-gn", ua.DevicePhone},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.DeviceBot},
+		{"Mozilla/5.0 (Apple Watch; CPU Watch OS 10_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/10.0 Mobile/21R355 Safari/605.1", ua.DeviceWearable},
+		{"Mozilla/5.0 (Linux; Android 11; Wear OS; SM-R870) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Mobile Safari/537.36", ua.DeviceWearable},
+		{"Mozilla/5.0 (Linux; Android 8.0.0; LEM12 Build/OPR1.170623.032; watch) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/88.0.4324.93 Mobile Safari/537.36", ua.DeviceWearable},
+		{"MyApp/1.0 (com.example.app; build:12; visionOS 1.0.1) Alamofire/5.8.1", ua.DeviceHeadset},
+		{"Mozilla/5.0 (Apple Vision Pro; CPU visionOS 1_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/21O211", ua.DeviceHeadset},
+		{"Mozilla/5.0 (X11; GNU/Linux) AppleWebKit/537.36 (KHTML, like Gecko) Chromium/79.0.3945.130 Chrome/79.0.3945.130 Safari/537.36 Tesla/2020.16.2.1-e99c70fff409", ua.DeviceEmbedded},
+		{"Mozilla/5.0 (SMART-FRIDGE; Linux; Tizen 5.5; Family Hub) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/2.1 Chrome/76.0.3809.146 Safari/537.36", ua.DeviceEmbedded},
+		{"Mozilla/5.0 (Linux; Android 9; BRAVIA 4K GB Build/PTT1.190515.001.S52) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.101 Mobile Safari/537.36", ua.DeviceTV},
+		{"Mozilla/5.0 (Linux; Android 7.1.2; AFTKMST12 Build/NS6291) AppleWebKit/537.36 (KHTML, like Gecko) Silk/86.3.20 like Chrome/86.0.4240.198 Safari/537.36", ua.DeviceTV},
+		{"Mozilla/5.0 (Linux; Android 12.0; Build/STTL.240206.002) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 CrKey/1.56.500000 DeviceType/AndroidTV", ua.DeviceTV},
+		{"Go-http-client/1.1", ua.DeviceUnknown},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.DeviceType() != test.deviceType {
+			t.Error("\n", test.ua, "\nDeviceType should be", test.deviceType, "not", agent.DeviceType())
+		}
+		if agent.Mobile && agent.Desktop || agent.Mobile && agent.Tablet || agent.Tablet && agent.Desktop {
+			t.Error("\n", test.ua, "\nmore than one device flag set")
+		}
+
+		// device type is kept in JSON
+		b, err := json.Marshal(agent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored ua.UserAgent
+		if err := json.Unmarshal(b, &restored); err != nil {
+			t.Fatal(err)
+		}
+		if restored.DeviceType() != test.deviceType || restored.Raw != agent.Raw || restored.Mobile != agent.Mobile {
+			t.Error("\n", test.ua, "\nDeviceType after JSON round trip should be", test.deviceType, "not", restored.DeviceType())
+		}
+	}
+
+	// Googlebot smartphone keeps the device it emulates
+	agent := ua.Parse("Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.6478.126 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	b, _ := json.Marshal(agent)
+	var restored ua.UserAgent
+	if err := json.Unmarshal(b, &restored); err != nil || restored.DeviceType() != ua.DeviceBot || restored.GooglebotType() != ua.GooglebotSmartphone {
+		t.Error("Googlebot smartphone should be restored from JSON, got", restored.DeviceType(), restored.GooglebotType(), err)
+	}
+
+	// JSON without device type derives it from the flags
+	restored = ua.UserAgent{}
+	if err := json.Unmarshal([]byte(`{"Name":"Safari","Tablet":true}`), &restored); err != nil || restored.DeviceType() != ua.DeviceTablet {
+		t.Error("device type should be derived from flags, got", restored.DeviceType(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"DeviceType":"toaster"}`), &restored); err == nil {
+		t.Error("unknown device type should return error")
+	}
+}
+
+func TestGeckoFamily(t *testing.T) {
+	for _, s := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:102.0) Gecko/20100101 Goanna/6.3 Firefox/102.0 PaleMoon/32.4.0.1",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:115.0) Gecko/20100101 Firefox/115.0 IceCat/115.5.0",
+	} {
+		if !ua.Parse(s).IsGeckoFamily() {
+			t.Error("\n", s, "\nshould be Gecko family")
+		}
+	}
+	s := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	if ua.Parse(s).IsGeckoFamily() {
+		t.Error("\n", s, "\nshould not be Gecko family")
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	for _, test := range loadCorpus(t) {
+		if bot := ua.Parse(test.UserAgent).Bot; ua.IsBot(test.UserAgent) != bot {
+			t.Error("\n", test.UserAgent, "\nIsBot should be", bot)
+		}
+	}
+}
+
+func TestIsMobile(t *testing.T) {
+	for _, test := range loadCorpus(t) {
+		agent := ua.Parse(test.UserAgent)
+		mobile := agent.Mobile || agent.Tablet
+		// desktop mode of Samsung Internet and Miui is not recognized by
+		// quick scan, they send desktop Linux user agent
+		if strings.Contains(test.UserAgent, "X11; Linux x86_64") {
+			mobile = false
+		}
+		if ua.IsMobile(test.UserAgent) != mobile {
+			t.Error("\n", test.UserAgent, "\nIsMobile should be", mobile)
+		}
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	tests := []struct {
+		ua     string
+		device string
+	}{
+		{"Mozilla/5.0 (Linux; Android 10; 红米Note 10S Build/QKQ1.200114.002) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/88.0.4324.93 Mobile Safari/537.36", "红米Note 10S"},
+		{"Mozilla/5.0 (Linux; Android 10; Redmi Note 10S\u3000Build/QKQ1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/88.0.4324.93 Mobile Safari/537.36", "Redmi Note 10S"},
+		{"Mozilla/5.0 (Linux; Android 10; Redmi\u00a0Note 10S Build/QKQ1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/88.0.4324.93 Mobile Safari/537.36", "Redmi Note 10S"},
+		{"Mozilla/5.0 (Linux; Android 10; Bad\xff\xfeDevice Build/QKQ1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/88.0.4324.93 Mobile Safari/537.36", "Bad\uFFFDDevice"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Device != test.device || agent.Name != ua.Chrome {
+			t.Errorf("\n%q\nDevice should be %q not %q", test.ua, test.device, agent.Device)
+		}
+		if agent.Raw != test.ua {
+			t.Errorf("\n%q\nRaw should keep the original user agent", test.ua)
+		}
+	}
+}
+
+func TestElectron(t *testing.T) {
+	tests := []struct {
+		ua         string
+		appName    string
+		appVersion string
+		electron   string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.33.90 Chrome/114.0.5735.289 Electron/25.5.0 Safari/537.36 Sonic Slack_SSB/4.33.90", "Slack", "4.33.90", "25.5.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) discord/1.0.9015 Chrome/108.0.5359.215 Electron/22.3.12 Safari/537.36", "Discord", "1.0.9015", "22.3.12"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Code/1.85.1 Chrome/114.0.5735.289 Electron/25.9.7 Safari/537.36", "VS Code", "1.85.1", "25.9.7"},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) MyNotes/2.0.1 Chrome/114.0.5735.289 Electron/25.8.1 Safari/537.36", "MyNotes", "2.0.1", "25.8.1"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.AppName != test.appName || agent.AppVersion != test.appVersion || agent.Electron != test.electron || agent.Name != ua.Chrome {
+			t.Error("\n", test.ua, "\nshould be", test.appName, test.appVersion, test.electron, "not", agent.Name, agent.AppName, agent.AppVersion, agent.Electron)
+		}
+	}
+}
+
+func TestTool(t *testing.T) {
+	tests := []struct {
+		ua     string
+		name   string
+		device string
+		tool   bool
+	}{
+		{"Dalvik/2.1.0 (Linux; U; Android 11; Pixel 4a Build/RQ3A.210805.001.A1)", "Dalvik", "Pixel 4a", true},
+		{"okhttp/4.9.0", "okhttp", "", true},
+		{"curl/7.68.0", "curl", "", true},
+		{"python-requests/2.31.0", "python-requests", "", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "", false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Device != test.device || agent.Tool != test.tool || agent.Bot {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.device, test.tool, "not", agent.Name, agent.Device, agent.Tool, agent.Bot)
+		}
+	}
+}
+
+func TestDownloadManager(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"aria2/1.36.0", "aria2", "1.36.0"},
+		{"wget2/2.1.0", "Wget2", "2.1.0"},
+		{"Axel 2.17.11 (Linux)", "Axel", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Free Download Manager/6.19", "Free Download Manager", "6.19"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:68.0) Gecko/20100101 Firefox/68.0 JDownloader", "JDownloader", ""},
+		{"youtube-dl/2021.12.17", "youtube-dl", "2021.12.17"},
+		{"yt-dlp/2023.12.30", "yt-dlp", "2023.12.30"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.Category != ua.CategoryDownloadManager || !agent.Tool || agent.Bot || agent.BrowserName != "" {
+			t.Error("\n", test.ua, "\nshould be download manager", test.name, test.version, "not", agent.Name, agent.Version, agent.Category, agent.Tool, agent.Bot)
+		}
+	}
+	if agent := ua.Parse("Wget/1.21.4"); agent.Category != "" || !agent.Tool {
+		t.Error("Wget should be tool without category, not", agent.Category)
+	}
+}
+
+func TestPackageManager(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+		os      string
+	}{
+		{"Homebrew/4.2.5 (Macintosh; arm64 Mac OS X 14.2.1) curl/8.4.0", "Homebrew", "4.2.5", ua.MacOS},
+		{`pip/23.3.1 {"ci":null,"cpu":"x86_64","implementation":{"name":"CPython","version":"3.11.6"},"installer":{"name":"pip","version":"23.3.1"},"python":"3.11.6"}`, "pip", "23.3.1", ""},
+		{"npm/10.2.4 node/v20.11.0 darwin arm64 workspaces/false", "npm", "10.2.4", ""},
+		{"pnpm/8.15.1 npm/? node/v20.11.0 darwin arm64", "pnpm", "8.15.1", ""},
+		{"yarn/1.22.19 npm/? node/v18.19.0 linux x64", "Yarn", "1.22.19", ""},
+		{"cargo 1.75.0 (1d8b05cdd 2023-11-20)", "Cargo", "1.75.0", ""},
+		{"Composer/2.6.6 (Darwin; 23.2.0; PHP 8.3.1; cURL 8.4.0; Platform-PHP 8.3.1)", "Composer", "2.6.6", ""},
+		{"NuGet Command Line/6.7.0 (Microsoft Windows NT 10.0.22621.0)", "NuGet", "6.7.0", ""},
+		{"NuGet .NET Core MSBuild Task/6.8.0 (Microsoft Windows 10.0.22631)", "NuGet", "6.8.0", ""},
+		{`docker/24.0.7 go/go1.20.10 git-commit/311b9ff kernel/6.5.0-14-generic os/linux arch/amd64 UpstreamClient(Docker-Client/24.0.7 \(linux\))`, "Docker", "24.0.7", ""},
+		{"Helm/3.13.3", "Helm", "3.13.3", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.OS != test.os || agent.Category != ua.CategoryPackageManager || !agent.Tool || agent.Bot {
+			t.Error("\n", test.ua, "\nshould be package manager", test.name, test.version, test.os, "not", agent.Name, agent.Version, agent.OS, agent.Category, agent.Tool, agent.Bot)
+		}
+	}
+}
+
+func TestDevTool(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+		bot     bool
+	}{
+		{"git/2.43.0", "git", "2.43.0", false},
+		{"git/2.39.3 (Apple Git-145)", "git", "2.39.3", false},
+		{"JGit/6.8.0.202311291450-r", "JGit", "6.8.0.202311291450-r", false},
+		{"gitlab-runner 16.7.0 (16-7-stable; go1.21.5; linux/amd64)", "GitLab Runner", "16.7.0", false},
+		{"Jenkins/2.426.2", "Jenkins", "2.426.2", false},
+		{"argocd-repo-server/v2.9.3 (linux/amd64)", "Argo CD", "2.9.3", false},
+		{"ArgoCD/v2.9.3 (linux/amd64)", "Argo CD", "2.9.3", false},
+		{"GitHub-Hookshot/a1b2c3d", "GitHub-Hookshot", "a1b2c3d", true},
+		{"Bitbucket-Webhooks/2.0", "Bitbucket-Webhooks", "2.0", true},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.Category != ua.CategoryDevTool || agent.Bot != test.bot || agent.Tool == test.bot {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.version, "bot", test.bot, "not", agent.Name, agent.Version, agent.Category, agent.Bot, agent.Tool)
+		}
+		if ua.IsBot(test.ua) != test.bot {
+			t.Error(test.ua, "IsBot should be", test.bot)
+		}
+	}
+}
+
+func TestClientWordStart(t *testing.T) {
+	// client hints are matched only at the start of a word
+	tests := []struct {
+		ua   string
+		name string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0 Digit/1", ua.Opera},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0 Spip/3.2", ua.Opera},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Category != "" || agent.Tool || agent.Bot {
+			t.Error("\n", test.ua, "\nshould be", test.name, "not", agent.Name, agent.Category, agent.Tool, agent.Bot)
+		}
+	}
+}
+
+func TestWindowsArch(t *testing.T) {
+	tests := []struct {
+		ua     string
+		arch   string
+		device string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "x64", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; ARM64; rv:120.0) Gecko/20100101 Firefox/120.0", "arm64", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; ARM64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "arm64", ""},
+		{"Mozilla/5.0 (compatible; MSIE 10.0; Windows NT 6.2; ARM; Trident/6.0; Touch; Surface)", "arm", "Surface"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; Surface Hub) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "x64", "Surface Hub"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Arch != test.arch || agent.Device != test.device || !agent.Desktop {
+			t.Error("\n", test.ua, "\nshould be", test.arch, test.device, "not", agent.Arch, agent.Device, agent.DeviceType())
+		}
+		if test.device != "" && agent.DeviceVendor != "Microsoft" {
+			t.Error(test.ua, "DeviceVendor should be Microsoft, not", agent.DeviceVendor)
+		}
+	}
+}
+
+func TestCFNetwork(t *testing.T) {
+	tests := []struct {
+		ua         string
+		os         string
+		osVersion  string
+		appName    string
+		appVersion string
+	}{
+		{"MyApp/123 CFNetwork/1474 Darwin/23.2.0", ua.IOS, "17", "MyApp", "123"},
+		{"Weather%20Pro/5.2.1 CFNetwork/1408.0.4 Darwin/22.5.0", ua.IOS, "16", "Weather Pro", "5.2.1"},
+		{"Mail/3774.300.61 CFNetwork/1494.0.7 Darwin/23.4.0 (x86_64)", ua.MacOS, "14", "Mail", "3774.300.61"},
+		{"Photos/9.0 CFNetwork/1126 Darwin/19.5.0 (arm64)", ua.MacOS, "10.15", "Photos", "9.0"},
+		{"MyApp/1 CFNetwork/758.0.2 Darwin/15.0.0", ua.IOS, "9", "MyApp", "1"},
+		{"MyApp/1 CFNetwork/1 Darwin/99.0.0", ua.IOS, "", "MyApp", "1"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != "CFNetwork" || agent.OS != test.os || agent.OSVersion != test.osVersion || agent.AppName != test.appName || agent.AppVersion != test.appVersion {
+			t.Error("\n", test.ua, "\nshould be", test.os, test.osVersion, test.appName, test.appVersion, "not", agent.Name, agent.OS, agent.OSVersion, agent.AppName, agent.AppVersion)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		ua     string
+		screen ua.Screen
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 261.0.0.21.111 (iPhone14,5; iOS 16_1; en_US; en; scale=3.00; 1170x2532; 414013834)", ua.Screen{Width: 1170, Height: 2532, Scale: 3}},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S908E Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36 Instagram 275.0.0.27.98 Android (33/13; 420dpi; 1080x2186; samsung; SM-S908E; b0q; qcom; en_US; 458229237)", ua.Screen{Width: 1080, Height: 2186, Scale: 2.625}},
+		{"Mozilla/5.0 (Linux; Android 12; SM-A525F Build/SP1A.210812.016; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36 [FB_IAB/FB4A;FBAV/423.0.0.21.64;FBDM/{density=2.625,width=1080,height=2186};FBLC/en_US;]", ua.Screen{Width: 1080, Height: 2186, Scale: 2.625}},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 [FBAN/FBIOS;FBDV/iPhone14,2;FBMD/iPhone;FBSN/iOS;FBSV/16.5;FBSS/3;FBID/phone;FBLC/en_US;FBOP/5;FBRV/0]", ua.Screen{Scale: 3}},
+		{"Aweme/26.2.0 (iPhone; iOS 14.4.2; Scale/3.00)", ua.Screen{Scale: 3}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Screen{}},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Screen != test.screen {
+			t.Error("\n", test.ua, "\nScreen should be", test.screen, "not", agent.Screen)
+		}
+	}
+}
+
+func TestTextBrowser(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"Lynx/2.8.9rel.1 libwww-FM/2.14 SSL-MM/1.4.1 OpenSSL/1.1.1d", ua.Lynx, "2.8.9rel.1"},
+		{"w3m/0.5.3+git20190105", ua.W3m, "0.5.3+git20190105"},
+		{"ELinks/0.13.GIT (textmode; Linux 4.19.0-6-amd64 x86_64; 213x57-2)", ua.ELinks, "0.13.GIT"},
+		{"Links (2.1pre26; Linux 2.6.26-2-686 i686; 80x24)", ua.Links, "2.1pre26"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || !agent.TextBrowser {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.version, "not", agent.Name, agent.Version, agent.TextBrowser)
+		}
+		if agent.DeviceType() != ua.DeviceUnknown || agent.Screen != (ua.Screen{}) {
+			t.Error("\n", test.ua, "\ndevice should be unknown, got", agent.DeviceType(), agent.Screen)
+		}
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	tests := []struct {
+		ua       string
+		name     string
+		prefetch bool
+		bot      bool
+	}{
+		{"Chrome Privacy Preserving Prefetch Proxy", ua.ChromePrefetchProxy, true, false},
+		{"Mozilla/5.0 (en-us) AppleWebKit/525.13 (KHTML, like Gecko; Google Web Preview) Version/3.1 Safari/525.13", ua.GoogleWebPreview, true, true},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 7_0 like Mac OS X) AppleWebKit/537.51.1 (KHTML, like Gecko) Version/7.0 Mobile/11A465 Safari/9537.53 BingPreview/1.0b", ua.BingPreview, true, true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_1) AppleWebKit/601.2.4 (KHTML, like Gecko) Version/9.0.1 Safari/601.2.4 facebookexternalhit/1.1 Facebot Twitterbot/1.0", ua.IMessagePreview, true, true},
+		{"com.apple.WebKit.Networking/8614.2.9.0.11 CFNetwork/1399 Darwin/22.1.0", ua.IMessagePreview, true, true},
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", ua.FacebookExternalHit, false, true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, false, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Prefetch != test.prefetch || agent.Bot != test.bot {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.prefetch, test.bot, "not", agent.Name, agent.Prefetch, agent.Bot)
+		}
+	}
+
+	headers := []struct {
+		name, value string
+		prefetch    bool
+	}{
+		{"Sec-Purpose", "prefetch;prerender", true},
+		{"Purpose", "prefetch", true},
+		{"X-Purpose", "preview", true},
+		{"X-Moz", "prefetch", true},
+		{"Accept", "text/html", false},
+	}
+	for _, test := range headers {
+		h := http.Header{}
+		h.Set(test.name, test.value)
+		if ua.IsPrefetchRequest(h) != test.prefetch {
+			t.Error(test.name, test.value, "IsPrefetchRequest should be", test.prefetch)
+		}
+	}
+}
+
+func TestAutomationTool(t *testing.T) {
+	tests := []struct {
+		ua   string
+		name string
+		tool string
+	}{
+		{"Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/538.1 (KHTML, like Gecko) PhantomJS/2.1.1 Safari/538.1", ua.PhantomJS, ua.PhantomJS},
+		{"Selenium/4.16.1 (java windows)", ua.Selenium, ua.Selenium},
+		{"Playwright/1.40.0 (x64; ubuntu 22.04) node/18.19", ua.Playwright, ua.Playwright},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/602.1 (KHTML, like Gecko) splash Version/10.0 Safari/602.1", ua.Splash, ua.Splash},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36", ua.HeadlessChrome, ua.HeadlessChrome},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.6099.109 Electron/28.1.0 Safari/537.36", ua.HeadlessChrome, "Electron"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.AutomationTool != test.tool || agent.Bot != (test.tool != "") {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.tool, "not", agent.Name, agent.AutomationTool, agent.Bot)
+		}
+		if ua.IsBot(test.ua) != agent.Bot {
+			t.Error(test.ua, "IsBot should be", agent.Bot)
+		}
+	}
+}
+
+func TestScanner(t *testing.T) {
+	tests := []struct {
+		ua   string
+		name string
+	}{
+		{"Mozilla/5.0 (compatible; Nessus; NASL)", "Nessus"},
+		{"Mozilla/5.00 (Nikto/2.1.6) (Evasions:None) (Test:000001)", "Nikto"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/95.0.4638.69 Safari/537.36 Nuclei - Open-source project (github.com/projectdiscovery/nuclei)", "Nuclei"},
+		{"Mozilla/5.0 (compatible; Shodan/1.0; +https://www.shodan.io/)", "Shodan"},
+		{"Hello from Palo Alto Networks, find out more about our scans in https://docs-cortex.paloaltonetworks.com/r/1/Cortex-Xpanse/Scanning-activity", "Palo Alto Networks"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Category != ua.CategoryScanner || !agent.Bot {
+			t.Error("\n", test.ua, "\nshould be scanner", test.name, "not", agent.Name, agent.Category, agent.Bot)
+		}
+		if !ua.IsBot(test.ua) {
+			t.Error(test.ua, "IsBot should be true")
+		}
+	}
+	if agent := ua.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"); agent.Category != "" {
+		t.Error("Chrome category should be empty, not", agent.Category)
+	}
+}
+
+func TestFeedReader(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"Feedly/1.0 (+http://www.feedly.com/fetcher.html; 16 subscribers; like FeedFetcher-Google)", "Feedly", "1.0"},
+		{"Mozilla/5.0 (compatible; inoreader.com; 3 subscribers)", "Inoreader", ""},
+		{"NewsBlur Feed Fetcher - 5 subscribers - https://www.newsblur.com/site/1234/blog (Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15)", "NewsBlur", ""},
+		{"PocketCasts/1.0 (Pocket Casts Feed Parser; +http://pocketcasts.com/)", "Pocket Casts", "1.0"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.Category != ua.CategoryFeedReader || agent.Bot {
+			t.Error("\n", test.ua, "\nshould be feed reader", test.name, test.version, "not", agent.Name, agent.Version, agent.Category, agent.Bot)
+		}
+		if ua.IsBot(test.ua) {
+			t.Error(test.ua, "IsBot should be false")
+		}
+	}
+}
+
+func TestMediaPlayer(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+		os      string
+		device  string
+	}{
+		{"VLC/3.0.20 LibVLC/3.0.20", "VLC", "3.0.20", "", ""},
+		{"Lavf/60.3.100", "Lavf", "60.3.100", "", ""},
+		{"Lavf53.32.100", "Lavf", "53.32.100", "", ""},
+		{"GStreamer souphttpsrc 1.22.0 libsoup/3.4.4", "GStreamer", "1.22.0", "", ""},
+		{"gvfs/1.50.2 GStreamer/1.20.3", "GStreamer", "1.20.3", "", ""},
+		{"ExoPlayerLib/2.19.1", "ExoPlayer", "2.19.1", "", ""},
+		{"com.google.android.youtube/19.02.39 (Linux; U; Android 14) ExoPlayerLib/2.18.1", "ExoPlayer", "2.18.1", ua.Android, ""},
+		{"AndroidXMedia3/1.1.1", "ExoPlayer", "1.1.1", "", ""},
+		{"AppleCoreMedia/1.0.0.20G75 (iPhone; U; CPU OS 16_6 like Mac OS X; en_us)", "AppleCoreMedia", "1.0.0.20G75", ua.IOS, "iPhone"},
+		{"stagefright/1.2 (Linux;Android 5.1.1)", "stagefright", "1.2", ua.Android, ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version || agent.Category != ua.CategoryMediaPlayer || agent.Bot || agent.Tool {
+			t.Error("\n", test.ua, "\nshould be media player", test.name, test.version, "not", agent.Name, agent.Version, agent.Category, agent.Bot, agent.Tool)
+		}
+		if agent.OS != test.os || agent.Device != test.device {
+			t.Error("\n", test.ua, "\nOS and device should be", test.os, test.device, "not", agent.OS, agent.Device)
+		}
+	}
+}
+
+func TestParseLite(t *testing.T) {
+	for _, s := range []string{
+		"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"",
+	} {
+		agent := ua.Parse(s)
+		want := ua.Lite{Name: agent.Name, Version: agent.Version, OS: agent.OS, Mobile: agent.Mobile, Bot: agent.Bot}
+		if lite := ua.ParseLite(s); lite != want {
+			t.Errorf("\n%s\nParseLite should be %+v not %+v", s, want, lite)
+		}
+	}
+
+	// fallback name and matcher versions are copied, not kept in the user agent memory
+	p := ua.NewParser()
+	if err := p.AddMatcher(0, `^AcmeOS-([\d.]+)`, func(agent *ua.UserAgent, m []string) {
+		agent.Name, agent.Version = "AcmeOS", m[1]
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"AcmeOS-3.2 (Linux; U; Android 11)", "unknown client"} {
+		lite := p.ParseLite(s)
+		if lite.Name == "" || sharesMemory(lite.Name, s) || sharesMemory(lite.Version, s) {
+			t.Errorf("\n%s\nParseLite result %+v should not share memory with the user agent", s, lite)
+		}
+	}
+}
+
+// sharesMemory returns true if non empty sub is stored in the memory of s
+func sharesMemory(sub, s string) bool {
+	if sub == "" || s == "" {
+		return false
+	}
+	start := *(*uintptr)(unsafe.Pointer(&s))
+	p := *(*uintptr)(unsafe.Pointer(&sub))
+	return p >= start && p < start+uintptr(len(s))
+}
+
+func TestEqual(t *testing.T) {
+	a := ua.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	b := ua.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 ")
+	if a.Raw == b.Raw || !ua.Equal(a, b) || a.Hash() != b.Hash() {
+		t.Error("user agents differing only in raw string should be equal with the same hash")
+	}
+	c := ua.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36")
+	if ua.Equal(a, c) || a.Hash() == c.Hash() {
+		t.Error("user agents with different versions should not be equal")
+	}
+	// hash must be the same in every process and release
+	if h := (ua.UserAgent{Name: ua.Chrome, Version: "120.0"}).Hash(); h != 0xdf6cdc734e10f583 {
+		t.Errorf("Hash changed to %#x", h)
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	tests := []struct {
+		ua        string
+		version   string
+		osVersion string
+		device    ua.DeviceType
+	}{
+		{"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", "23", "13", ua.DevicePhone},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", "10", "10", ua.DeviceTablet},
+		{"Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.5414.120 Safari/537.36", "109", "6.1", ua.DeviceDesktop},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua).Anonymize()
+		if agent.Version != test.version || agent.OSVersion != test.osVersion || agent.DeviceType() != test.device {
+			t.Error("\n", test.ua, "\nshould be anonymized to", test.version, test.osVersion, test.device, "not", agent.Version, agent.OSVersion, agent.DeviceType())
+		}
+		if agent.Raw != "" || agent.Device != "" || agent.DeviceModel != "" || agent.Locale != "" || agent.MajorVersion() == 0 {
+			t.Errorf("\n%s\nidentifying fields should be removed, %+v", test.ua, agent)
+		}
+	}
+
+	// region is derived from the locale
+	agent := ua.Parse("Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 270.0.0.13.83 (iPhone13,2; iOS 16_3; es_ES; es-ES; scale=3.00; 1170x2532; 445843881) NW/1").Anonymize()
+	if agent.Locale != "" || agent.Region != "" {
+		t.Error("locale and region should be removed, not", agent.Locale, agent.Region)
+	}
+
+	// name of unrecognized user agent is the raw user agent
+	for _, s := range []string{
+		"MyApp user=jane.doe@example.com session=8f3a2c",
+		"some random crawler +https://example.com/about",
+	} {
+		if agent := ua.Parse(s).Anonymize(); agent.Name != "" || agent.URL != "" || agent.BotContact != "" {
+			t.Errorf("\n%s\nraw name should be removed, %+v", s, agent)
+		}
+	}
+	if agent := ua.Parse("AcmeCrawler (+http://example.com/crawler; ops@example.com)").Anonymize(); agent.Name != "AcmeCrawler" || !agent.Bot {
+		t.Error("recognized name should be kept, not", agent.Name)
+	}
+}
+
+func TestEReader(t *testing.T) {
+	for _, s := range []string{
+		"Mozilla/5.0 (X11; U; Linux armv7l like Android; en-us) AppleWebKit/531.2+ (KHTML, like Gecko) Version/5.0 Safari/531.2+ Kindle/3.0+",
+		"Mozilla/5.0 (Linux; U; Android 2.0; en-us;) AppleWebKit/538.1 (KHTML, like Gecko) Version/4.0 Mobile Safari/538.1 (Kobo Touch 0373/4.38.21908)",
+		"Mozilla/5.0 (Linux; U; en-US) AppleWebKit/534.34 (KHTML, like Gecko) PocketBook/622 (screen 600x800; Qt/4.8.5) Version/1.0 Safari/534.34",
+		"Mozilla/5.0 (Linux; Android 10; BOOX Note Air2 Build/QKQ1.200126.002) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/83.0.4103.106 Safari/537.36",
+	} {
+		agent := ua.Parse(s)
+		if !agent.EReader || agent.Mobile || agent.Desktop {
+			t.Error("\n", s, "should be e-reader only")
+		}
 	}
 }
 
 var testUA ua.UserAgent
 
 func BenchmarkUserAgent(b *testing.B) {
+	corpus := loadCorpus(b)
+	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		for _, test := range testTable {
-			testUA = ua.Parse(test[0])
+		for _, test := range corpus {
+			testUA = ua.Parse(test.UserAgent)
+		}
+	}
+}
+
+func TestDesktopMode(t *testing.T) {
+	tests := []struct {
+		ua          string
+		desktopMode bool
+		deviceType  ua.DeviceType
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64; DeX) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/9.2 Chrome/67.0.3396.87 Safari/537.36", true, ua.DeviceDesktop},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36", true, ua.DevicePhone},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Mobile/15E148 Safari/604.1", true, ua.DeviceTablet},
+		{"Mozilla/5.0 (Linux; Android 12; SAMSUNG SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36", false, ua.DeviceTablet},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false, ua.DeviceDesktop},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.DesktopMode != test.desktopMode || agent.DeviceType() != test.deviceType {
+			t.Error("\n", test.ua, "\nDesktopMode should be", test.desktopMode, test.deviceType, "not", agent.DesktopMode, agent.DeviceType())
+		}
+		if agent.Name == ua.SamsungBrowser && agent.DesktopMode && (agent.OS != ua.Android || agent.OSVersion != "") {
+			t.Error("\n", test.ua, "\nOS should be Android without version, not", agent.OS, agent.OSVersion)
+		}
+	}
+}
+
+func TestChineseApps(t *testing.T) {
+	tests := []struct {
+		ua         string
+		name       string
+		appName    string
+		appVersion string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 MicroMessenger/8.0.40(0x1800282a) NetType/WIFI Language/zh_CN", ua.WeChatApp, "WeChat", "8.0.40"},
+		{"Mozilla/5.0 (Linux; Android 12; PEHM00 Build/SKQ1.210216.001; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.72 MQQBrowser/6.2 TBS/046247 Mobile Safari/537.36 V1_AND_SQ_8.9.75_4450_YYB_D QQ/8.9.75.12440 NetType/WIFI WebP/0.3.0", ua.QQApp, "QQ", "8.9.75.12440"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Weibo (iPhone14,2__weibo__13.8.1__iphone__os16.6)", ua.WeiboApp, "Weibo", "13.8.1"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/20G75 Ariver/1.1.0 AliApp(AP/10.5.20.6000) Nebula WK RVKType(0) AlipayDefined(nt:WIFI,ws:390|780|3.0) AlipayClient/10.5.20.6000 Language/zh-Hans Region/CN", ua.AlipayApp, "Alipay", "10.5.20.6000"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 SP-engine/2.80.0 main%2F1.0 baiduboxapp/13.40.0.10 (Baidu; P2 16.6) NABar/1.0", ua.BaiduApp, "Baidu", "13.40.0.10"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.appVersion || agent.AppName != test.appName || agent.AppVersion != test.appVersion || !agent.WebView || !agent.Mobile {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.appName, test.appVersion, "not", agent.Name, agent.AppName, agent.AppVersion, agent.WebView, agent.Mobile)
+		}
+	}
+}
+
+func TestCaseInsensitiveTokens(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"Mozilla/5.0 (compatible; BingBot/2.0; +http://www.bing.com/bingbot.htm)", ua.Bingbot, "2.0"},
+		{"Mozilla/5.0 (compatible; GoogleBot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1"},
+		{"Mozilla/5.0 (compatible; yandexbot/3.0; +http://yandex.com/bots)", ua.YandexBot, "3.0"},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Samsungbrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", ua.SamsungBrowser, "23.0"},
+		{"Mozilla/5.0 (Linux; U; Android 11; Redmi Note 10S Build/RP1A.200720.011) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.116 Mobile Safari/537.36 Xiaomi/miuibrowser/12.13.2-gn", "Miui Browser", "12.13.2-gn"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Error("\n", test.ua, "\nName should be", test.name, test.version, "not", agent.Name, agent.Version)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		ua                      string
+		app, appVersion         string
+		browser, browserVersion string
+		engine, engineVersion   string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 270.0.0.13.83 (iPhone13,2; iOS 16_3; es_ES; es-ES; scale=3.00; 1170x2532; 445843881) NW/1", "Instagram", "270.0.0.13.83", ua.Safari, "", ua.WebKit, "605.1.15"},
+		{"Mozilla/5.0 (Linux; Android 13; SM-T220 Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/109.0.5414.117 Safari/537.36 [FB_IAB/FB4A;FBAV/400.0.0.37.76;]", "Facebook", "400.0.0.37.76", ua.Chrome, "109.0.5414.117", ua.Blink, "109.0.5414.117"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.33.90 Chrome/114.0.5735.289 Electron/25.5.0 Safari/537.36 Sonic Slack_SSB/4.33.90", "Slack", "4.33.90", ua.Chrome, "114.0.5735.289", ua.Blink, "114.0.5735.289"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", "", "", ua.Chrome, "120.0.6099.119", ua.WebKit, "605.1.15"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", "", "", ua.Firefox, "121.0", ua.Gecko, "121.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.17763", "", "", ua.Edge, "18.17763", ua.EdgeHTML, "18.17763"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "", "", "", "", "", ""},
+		{"curl/8.4.0", "", "", "", "", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.AppName != test.app || agent.AppVersion != test.appVersion ||
+			agent.BrowserName != test.browser || agent.BrowserVersion != test.browserVersion ||
+			agent.Engine != test.engine || agent.EngineVersion != test.engineVersion {
+			t.Error("\n", test.ua, "\nshould be", test.app, test.appVersion, test.browser, test.browserVersion, test.engine, test.engineVersion,
+				"\nnot      ", agent.AppName, agent.AppVersion, agent.BrowserName, agent.BrowserVersion, agent.Engine, agent.EngineVersion)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	for _, s := range []string{"", " ", "\t", "-", " - "} {
+		agent := ua.Parse(s)
+		if !ua.Equal(agent, ua.UserAgent{}) || agent.Raw != s || !agent.IsUnknown() {
+			t.Errorf("%q should be zero value unknown user agent, got %+v", s, agent)
 		}
 	}
 }
@@ -276,8 +1011,8 @@ func ExampleParse() {
 	for _, s := range userAgents {
 		ua := ua.Parse(s)
 		fmt.Println()
-		fmt.Println(ua.String)
-		fmt.Println(strings.Repeat("=", len(ua.String)))
+		fmt.Println(ua.Raw)
+		fmt.Println(strings.Repeat("=", len(ua.Raw)))
 		fmt.Println("Name:", ua.Name, "v", ua.Version)
 		fmt.Println("OS:", ua.OS, "v", ua.OSVersion)
 		fmt.Println("Device:", ua.Device)
@@ -0,0 +1,592 @@
+// Package uapb provides protocol buffers representation of the parsed user
+// agent, defined in useragent.proto, so parse results can be passed through
+// gRPC pipelines and stored with a stable schema.
+//
+// Types mirror the code generated by protoc-gen-go and are encoded in
+// the protobuf wire format, but they are written by hand, so the package has
+// no dependencies. Services using generated code can generate it from
+// useragent.proto, messages are interchangeable on the wire.
+package uapb
+
+import (
+	"errors"
+	"math"
+
+	"github.com/mileusna/useragent"
+)
+
+// DeviceType of the user agent, values match useragent.DeviceType
+type DeviceType int32
+
+// DeviceType values
+const (
+	DeviceType_DEVICE_TYPE_UNKNOWN  DeviceType = 0
+	DeviceType_DEVICE_TYPE_PHONE    DeviceType = 1
+	DeviceType_DEVICE_TYPE_TABLET   DeviceType = 2
+	DeviceType_DEVICE_TYPE_DESKTOP  DeviceType = 3
+	DeviceType_DEVICE_TYPE_TV       DeviceType = 4
+	DeviceType_DEVICE_TYPE_CONSOLE  DeviceType = 5
+	DeviceType_DEVICE_TYPE_WEARABLE DeviceType = 6
+	DeviceType_DEVICE_TYPE_EMBEDDED DeviceType = 7
+	DeviceType_DEVICE_TYPE_BOT      DeviceType = 8
+	DeviceType_DEVICE_TYPE_HEADSET  DeviceType = 9
+)
+
+// VersionNo is parsed version number
+type VersionNo struct {
+	Major  int32
+	Minor  int32
+	Patch  int32
+	Suffix string
+	Build  int32
+}
+
+// Screen size and scale sent by in-app browsers
+type Screen struct {
+	Width  int32
+	Height int32
+	Scale  float64
+}
+
+// UserAgent is parse result. DeviceType of bots is the device the bot
+// is emulating, like phone for Googlebot smartphone.
+type UserAgent struct {
+	Raw             string
+	Name            string
+	Version         string
+	VersionNo       *VersionNo
+	Os              string
+	OsVersion       string
+	OsVersionNo     *VersionNo
+	Device          string
+	DeviceType      DeviceType
+	Url             string
+	Bot             bool
+	BotReason       string
+	VerifiedBot     bool
+	Tool            bool
+	Ereader         bool
+	Arch            string
+	Locale          string
+	AndroidBuild    string
+	HmsCore         string
+	Webview         bool
+	MaybeIpad       bool
+	AppName         string
+	AppVersion      string
+	Electron        string
+	Screen          *Screen
+	Anomalies       []string
+	TextBrowser     bool
+	Prefetch        bool
+	DeviceVendor    string
+	DeviceModel     string
+	AutomationTool  string
+	Category        string
+	Truncated       bool
+	DesktopMode     bool
+	BrowserName     string
+	BrowserVersion  string
+	Engine          string
+	EngineVersion   string
+	BotContact      string
+	Region          string
+	Urls            []string
+	OsDistro        string
+	OsDistroVersion string
+	WindowSystem    string
+}
+
+// FromUserAgent converts parsed user agent to protobuf message
+func FromUserAgent(ua useragent.UserAgent) *UserAgent {
+	m := &UserAgent{
+		Raw:             ua.Raw,
+		Name:            ua.Name,
+		Version:         ua.Version,
+		VersionNo:       fromVersionNo(ua.VersionNo),
+		Os:              ua.OS,
+		OsVersion:       ua.OSVersion,
+		OsVersionNo:     fromVersionNo(ua.OSVersionNo),
+		Device:          ua.Device,
+		DeviceType:      DeviceType(ua.DeviceType()),
+		Url:             ua.URL,
+		Bot:             ua.Bot,
+		BotReason:       ua.BotReason,
+		VerifiedBot:     ua.VerifiedBot,
+		Tool:            ua.Tool,
+		Ereader:         ua.EReader,
+		Arch:            ua.Arch,
+		Locale:          ua.Locale,
+		AndroidBuild:    ua.AndroidBuild,
+		HmsCore:         ua.HMSCore,
+		Webview:         ua.WebView,
+		MaybeIpad:       ua.MaybeIPad,
+		AppName:         ua.AppName,
+		AppVersion:      ua.AppVersion,
+		Electron:        ua.Electron,
+		Anomalies:       ua.Anomalies,
+		TextBrowser:     ua.TextBrowser,
+		Prefetch:        ua.Prefetch,
+		DeviceVendor:    ua.DeviceVendor,
+		DeviceModel:     ua.DeviceModel,
+		AutomationTool:  ua.AutomationTool,
+		Category:        ua.Category,
+		Truncated:       ua.Truncated,
+		DesktopMode:     ua.DesktopMode,
+		BrowserName:     ua.BrowserName,
+		BrowserVersion:  ua.BrowserVersion,
+		Engine:          ua.Engine,
+		EngineVersion:   ua.EngineVersion,
+		BotContact:      ua.BotContact,
+		Region:          ua.Region,
+		Urls:            ua.URLs,
+		OsDistro:        ua.OSDistro,
+		OsDistroVersion: ua.OSDistroVersion,
+		WindowSystem:    ua.WindowSystem,
+	}
+	if ua.Bot {
+		// device type flags of bots describe the emulated device
+		switch {
+		case ua.Tablet:
+			m.DeviceType = DeviceType_DEVICE_TYPE_TABLET
+		case ua.Mobile:
+			m.DeviceType = DeviceType_DEVICE_TYPE_PHONE
+		case ua.Desktop:
+			m.DeviceType = DeviceType_DEVICE_TYPE_DESKTOP
+		default:
+			m.DeviceType = DeviceType_DEVICE_TYPE_UNKNOWN
+		}
+	}
+	if ua.Screen != (useragent.Screen{}) {
+		m.Screen = &Screen{
+			Width:  int32(ua.Screen.Width),
+			Height: int32(ua.Screen.Height),
+			Scale:  ua.Screen.Scale,
+		}
+	}
+	return m
+}
+
+// ToUserAgent converts protobuf message to user agent
+func (m *UserAgent) ToUserAgent() useragent.UserAgent {
+	ua := useragent.UserAgent{
+		Raw:             m.Raw,
+		String:          m.Raw,
+		Name:            m.Name,
+		Version:         m.Version,
+		VersionNo:       m.VersionNo.toVersionNo(),
+		OS:              m.Os,
+		OSVersion:       m.OsVersion,
+		OSVersionNo:     m.OsVersionNo.toVersionNo(),
+		Device:          m.Device,
+		URL:             m.Url,
+		Bot:             m.Bot,
+		BotReason:       m.BotReason,
+		VerifiedBot:     m.VerifiedBot,
+		Tool:            m.Tool,
+		EReader:         m.Ereader,
+		Arch:            m.Arch,
+		Locale:          m.Locale,
+		AndroidBuild:    m.AndroidBuild,
+		HMSCore:         m.HmsCore,
+		WebView:         m.Webview,
+		MaybeIPad:       m.MaybeIpad,
+		AppName:         m.AppName,
+		AppVersion:      m.AppVersion,
+		Electron:        m.Electron,
+		Anomalies:       m.Anomalies,
+		TextBrowser:     m.TextBrowser,
+		Prefetch:        m.Prefetch,
+		DeviceVendor:    m.DeviceVendor,
+		DeviceModel:     m.DeviceModel,
+		AutomationTool:  m.AutomationTool,
+		Category:        m.Category,
+		Truncated:       m.Truncated,
+		DesktopMode:     m.DesktopMode,
+		BrowserName:     m.BrowserName,
+		BrowserVersion:  m.BrowserVersion,
+		Engine:          m.Engine,
+		EngineVersion:   m.EngineVersion,
+		BotContact:      m.BotContact,
+		Region:          m.Region,
+		URLs:            m.Urls,
+		OSDistro:        m.OsDistro,
+		OSDistroVersion: m.OsDistroVersion,
+		WindowSystem:    m.WindowSystem,
+	}
+	ua.SetDeviceType(useragent.DeviceType(m.DeviceType))
+	if m.Screen != nil {
+		ua.Screen = useragent.Screen{
+			Width:  int(m.Screen.Width),
+			Height: int(m.Screen.Height),
+			Scale:  m.Screen.Scale,
+		}
+	}
+	return ua
+}
+
+func fromVersionNo(v useragent.VersionNo) *VersionNo {
+	if v == (useragent.VersionNo{}) {
+		return nil
+	}
+	return &VersionNo{Major: int32(v.Major), Minor: int32(v.Minor), Patch: int32(v.Patch), Build: int32(v.Build), Suffix: v.Suffix}
+}
+
+func (v *VersionNo) toVersionNo() useragent.VersionNo {
+	if v == nil {
+		return useragent.VersionNo{}
+	}
+	return useragent.VersionNo{Major: int(v.Major), Minor: int(v.Minor), Patch: int(v.Patch), Build: int(v.Build), Suffix: v.Suffix}
+}
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ErrInvalid is returned by Unmarshal for malformed messages
+var ErrInvalid = errors.New("uapb: invalid protobuf message")
+
+// Marshal returns protobuf wire encoding of the message
+func (m *UserAgent) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Raw)
+	b = appendString(b, 2, m.Name)
+	b = appendString(b, 3, m.Version)
+	b = appendMessage(b, 4, m.VersionNo.marshal())
+	b = appendString(b, 5, m.Os)
+	b = appendString(b, 6, m.OsVersion)
+	b = appendMessage(b, 7, m.OsVersionNo.marshal())
+	b = appendString(b, 8, m.Device)
+	b = appendInt32(b, 9, int32(m.DeviceType))
+	b = appendString(b, 10, m.Url)
+	b = appendBool(b, 11, m.Bot)
+	b = appendString(b, 12, m.BotReason)
+	b = appendBool(b, 13, m.VerifiedBot)
+	b = appendBool(b, 14, m.Tool)
+	b = appendBool(b, 15, m.Ereader)
+	b = appendString(b, 16, m.Arch)
+	b = appendString(b, 17, m.Locale)
+	b = appendString(b, 18, m.AndroidBuild)
+	b = appendString(b, 19, m.HmsCore)
+	b = appendBool(b, 20, m.Webview)
+	b = appendBool(b, 21, m.MaybeIpad)
+	b = appendString(b, 22, m.AppName)
+	b = appendString(b, 23, m.AppVersion)
+	b = appendString(b, 24, m.Electron)
+	b = appendMessage(b, 25, m.Screen.marshal())
+	for _, a := range m.Anomalies {
+		b = appendTag(b, 26, wireBytes)
+		b = appendVarint(b, uint64(len(a)))
+		b = append(b, a...)
+	}
+	b = appendBool(b, 27, m.TextBrowser)
+	b = appendBool(b, 28, m.Prefetch)
+	b = appendString(b, 29, m.DeviceVendor)
+	b = appendString(b, 30, m.DeviceModel)
+	b = appendString(b, 31, m.AutomationTool)
+	b = appendString(b, 32, m.Category)
+	b = appendBool(b, 33, m.Truncated)
+	b = appendBool(b, 34, m.DesktopMode)
+	b = appendString(b, 35, m.BrowserName)
+	b = appendString(b, 36, m.BrowserVersion)
+	b = appendString(b, 37, m.Engine)
+	b = appendString(b, 38, m.EngineVersion)
+	b = appendString(b, 39, m.BotContact)
+	b = appendString(b, 40, m.Region)
+	for _, u := range m.Urls {
+		b = appendTag(b, 41, wireBytes)
+		b = appendVarint(b, uint64(len(u)))
+		b = append(b, u...)
+	}
+	b = appendString(b, 42, m.OsDistro)
+	b = appendString(b, 43, m.OsDistroVersion)
+	b = appendString(b, 44, m.WindowSystem)
+	return b
+}
+
+// Unmarshal decodes protobuf wire encoding into the message,
+// unknown fields are skipped
+func (m *UserAgent) Unmarshal(b []byte) error {
+	*m = UserAgent{}
+	return decode(b, func(field int, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			m.Raw = string(data)
+		case 2:
+			m.Name = string(data)
+		case 3:
+			m.Version = string(data)
+		case 4:
+			m.VersionNo = &VersionNo{}
+			return m.VersionNo.unmarshal(data)
+		case 5:
+			m.Os = string(data)
+		case 6:
+			m.OsVersion = string(data)
+		case 7:
+			m.OsVersionNo = &VersionNo{}
+			return m.OsVersionNo.unmarshal(data)
+		case 8:
+			m.Device = string(data)
+		case 9:
+			m.DeviceType = DeviceType(v)
+		case 10:
+			m.Url = string(data)
+		case 11:
+			m.Bot = v != 0
+		case 12:
+			m.BotReason = string(data)
+		case 13:
+			m.VerifiedBot = v != 0
+		case 14:
+			m.Tool = v != 0
+		case 15:
+			m.Ereader = v != 0
+		case 16:
+			m.Arch = string(data)
+		case 17:
+			m.Locale = string(data)
+		case 18:
+			m.AndroidBuild = string(data)
+		case 19:
+			m.HmsCore = string(data)
+		case 20:
+			m.Webview = v != 0
+		case 21:
+			m.MaybeIpad = v != 0
+		case 22:
+			m.AppName = string(data)
+		case 23:
+			m.AppVersion = string(data)
+		case 24:
+			m.Electron = string(data)
+		case 25:
+			m.Screen = &Screen{}
+			return m.Screen.unmarshal(data)
+		case 26:
+			m.Anomalies = append(m.Anomalies, string(data))
+		case 27:
+			m.TextBrowser = v != 0
+		case 28:
+			m.Prefetch = v != 0
+		case 29:
+			m.DeviceVendor = string(data)
+		case 30:
+			m.DeviceModel = string(data)
+		case 31:
+			m.AutomationTool = string(data)
+		case 32:
+			m.Category = string(data)
+		case 33:
+			m.Truncated = v != 0
+		case 34:
+			m.DesktopMode = v != 0
+		case 35:
+			m.BrowserName = string(data)
+		case 36:
+			m.BrowserVersion = string(data)
+		case 37:
+			m.Engine = string(data)
+		case 38:
+			m.EngineVersion = string(data)
+		case 39:
+			m.BotContact = string(data)
+		case 40:
+			m.Region = string(data)
+		case 41:
+			m.Urls = append(m.Urls, string(data))
+		case 42:
+			m.OsDistro = string(data)
+		case 43:
+			m.OsDistroVersion = string(data)
+		case 44:
+			m.WindowSystem = string(data)
+		}
+		return nil
+	})
+}
+
+func (v *VersionNo) marshal() []byte {
+	if v == nil {
+		return nil
+	}
+	var b []byte
+	b = appendInt32(b, 1, v.Major)
+	b = appendInt32(b, 2, v.Minor)
+	b = appendInt32(b, 3, v.Patch)
+	b = appendString(b, 4, v.Suffix)
+	b = appendInt32(b, 5, v.Build)
+	return b
+}
+
+func (v *VersionNo) unmarshal(b []byte) error {
+	return decode(b, func(field int, wire int, x uint64, data []byte) error {
+		switch field {
+		case 1:
+			v.Major = int32(x)
+		case 2:
+			v.Minor = int32(x)
+		case 3:
+			v.Patch = int32(x)
+		case 4:
+			v.Suffix = string(data)
+		case 5:
+			v.Build = int32(x)
+		}
+		return nil
+	})
+}
+
+func (s *Screen) marshal() []byte {
+	if s == nil {
+		return nil
+	}
+	var b []byte
+	b = appendInt32(b, 1, s.Width)
+	b = appendInt32(b, 2, s.Height)
+	if s.Scale != 0 {
+		b = appendTag(b, 3, wireFixed64)
+		b = appendFixed64(b, math.Float64bits(s.Scale))
+	}
+	return b
+}
+
+func (s *Screen) unmarshal(b []byte) error {
+	return decode(b, func(field int, wire int, x uint64, data []byte) error {
+		switch field {
+		case 1:
+			s.Width = int32(x)
+		case 2:
+			s.Height = int32(x)
+		case 3:
+			s.Scale = math.Float64frombits(x)
+		}
+		return nil
+	})
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return appendVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+// appendString appends string field, empty strings are omitted as in proto3
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendMessage appends embedded message, nil messages are omitted
+func appendMessage(b []byte, field int, msg []byte) []byte {
+	if msg == nil {
+		return b
+	}
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+func appendBool(b []byte, field int, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return append(b, 1)
+}
+
+// appendInt32 appends int32 field, negative values are sign extended
+// to 64 bits as in protobuf
+func appendInt32(b []byte, field int, v int32) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return appendVarint(b, uint64(int64(v)))
+}
+
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// decode calls f for each field of the message with varint or fixed value v,
+// or data of length delimited fields
+func decode(b []byte, f func(field int, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := readVarint(b)
+		if n == 0 {
+			return ErrInvalid
+		}
+		b = b[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		if field == 0 {
+			return ErrInvalid
+		}
+		var v uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			if v, n = readVarint(b); n == 0 {
+				return ErrInvalid
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return ErrInvalid
+			}
+			for i := 0; i < 8; i++ {
+				v |= uint64(b[i]) << (8 * uint(i))
+			}
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return ErrInvalid
+			}
+			for i := 0; i < 4; i++ {
+				v |= uint64(b[i]) << (8 * uint(i))
+			}
+			b = b[4:]
+		case wireBytes:
+			l, n := readVarint(b)
+			if n == 0 || uint64(len(b)-n) < l {
+				return ErrInvalid
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			return ErrInvalid
+		}
+		if err := f(field, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package uapb_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/useragent"
+	"github.com/mileusna/useragent/uapb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"Mozilla/5.0 (Linux; Android 13; SM-S908E Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36 Instagram 275.0.0.27.98 Android (33/13; 420dpi; 1080x2186; samsung; SM-S908E; b0q; qcom; en_US; 458229237)",
+		"Mozilla/5.0 (Linux; Android 9; BRAVIA 4K GB Build/PTT1.190515.001.S52) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.101 Mobile Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 (",
+		"Lynx/2.8.9dev.16 libwww-FM/2.14 SSL-MM/1.4.1 GNUTLS/3.7.1",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 " + strings.Repeat("Extension/1.0 ", 150),
+	} {
+		ua := useragent.Parse(s)
+		var m uapb.UserAgent
+		if err := m.Unmarshal(uapb.FromUserAgent(ua).Marshal()); err != nil {
+			t.Fatal(s, err)
+		}
+		got := m.ToUserAgent()
+		if !reflect.DeepEqual(got, ua) {
+			t.Errorf("\n%s\nround trip should be\n%+v\nnot\n%+v", s, ua, got)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	m := &uapb.UserAgent{
+		Name:       "Chrome",
+		VersionNo:  &uapb.VersionNo{Major: 120},
+		DeviceType: uapb.DeviceType_DEVICE_TYPE_DESKTOP,
+		Bot:        true,
+		Anomalies:  []string{"x"},
+	}
+	want := []byte{
+		0x12, 6, 'C', 'h', 'r', 'o', 'm', 'e', // name
+		0x22, 2, 0x08, 120, // version_no
+		0x48, 3, // device_type
+		0x58, 1, // bot
+		0xd2, 0x01, 1, 'x', // anomalies
+	}
+	if b := m.Marshal(); !bytes.Equal(b, want) {
+		t.Errorf("Marshal should be\n% x\nnot\n% x", want, b)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x12, 6, 'C'},     // truncated string
+		{0x12},             // missing length
+		{0x08, 0x80},       // truncated varint
+		{0x00, 0x01},       // field 0
+		{0x0b, 0x01},       // group wire type
+		{0x22, 2, 0x0a, 5}, // truncated embedded message
+	} {
+		var m uapb.UserAgent
+		if err := m.Unmarshal(b); err != uapb.ErrInvalid {
+			t.Errorf("% x should be invalid, got %v", b, err)
+		}
+	}
+
+	// unknown fields are skipped
+	var m uapb.UserAgent
+	if err := m.Unmarshal([]byte{0xf8, 0x07, 1, 0x12, 1, 'A'}); err != nil || m.Name != "A" {
+		t.Error("unknown field not skipped", err, m.Name)
+	}
+}
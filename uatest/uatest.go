@@ -0,0 +1,167 @@
+// Package uatest provides ready-made user agents for tests, so projects using
+// useragent package don't have to hardcode long user agent strings in their
+// own tests. Fixtures are parsed UserAgent structs, the user agent string is
+// in the Raw field:
+//
+//	req.Header.Set("User-Agent", uatest.ChromeWindows().Raw)
+//
+// Browser fixtures are generated with useragent.Format, so they follow the
+// library, and the Name, Version and OS of the fixture are the ones used to
+// generate the user agent.
+package uatest
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/mileusna/useragent"
+)
+
+// Bot and tool user agents
+const (
+	googlebotUA           = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	googlebotSmartphoneUA = "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.129 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	bingbotUA             = "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)"
+	curlUA                = "curl/8.4.0"
+)
+
+// ChromeWindows returns Chrome on Windows 10
+func ChromeWindows() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Chrome, OS: useragent.Windows})
+}
+
+// ChromeMac returns Chrome on macOS
+func ChromeMac() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Chrome, OS: useragent.MacOS})
+}
+
+// ChromeAndroid returns Chrome on Android phone
+func ChromeAndroid() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Chrome, OS: useragent.Android})
+}
+
+// FirefoxWindows returns Firefox on Windows 10
+func FirefoxWindows() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Firefox, OS: useragent.Windows})
+}
+
+// FirefoxLinux returns Firefox on Linux
+func FirefoxLinux() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Firefox, OS: useragent.Linux})
+}
+
+// EdgeWindows returns Edge on Windows 10
+func EdgeWindows() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Edge, OS: useragent.Windows})
+}
+
+// SafariMac returns Safari on macOS
+func SafariMac() useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Safari, OS: useragent.MacOS})
+}
+
+// SafariIPhone returns Safari on iPhone, version is both Safari and iOS
+// version, like "17.2". Empty version is a recent version.
+func SafariIPhone(version string) useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Safari, Version: version, OS: useragent.IOS, OSVersion: version})
+}
+
+// SafariIPad returns Safari on iPad, version is both Safari and iOS
+// version, like "17.2". Empty version is a recent version.
+func SafariIPad(version string) useragent.UserAgent {
+	return format(useragent.UserAgent{Name: useragent.Safari, Version: version, OS: useragent.IOS, OSVersion: version, Tablet: true})
+}
+
+// Googlebot returns desktop Googlebot
+func Googlebot() useragent.UserAgent {
+	return useragent.Parse(googlebotUA)
+}
+
+// GooglebotSmartphone returns Googlebot crawling as Android phone
+func GooglebotSmartphone() useragent.UserAgent {
+	return useragent.Parse(googlebotSmartphoneUA)
+}
+
+// Bingbot returns desktop Bingbot
+func Bingbot() useragent.UserAgent {
+	return useragent.Parse(bingbotUA)
+}
+
+// Curl returns curl command line tool
+func Curl() useragent.UserAgent {
+	return useragent.Parse(curlUA)
+}
+
+// combination of the browser and OS supported by useragent.Format
+type combination struct {
+	name string
+	os   string
+}
+
+var combinations = []combination{
+	{useragent.Chrome, useragent.Windows},
+	{useragent.Chrome, useragent.MacOS},
+	{useragent.Chrome, useragent.Linux},
+	{useragent.Chrome, useragent.ChromeOS},
+	{useragent.Chrome, useragent.Android},
+	{useragent.Chrome, useragent.IOS},
+	{useragent.Firefox, useragent.Windows},
+	{useragent.Firefox, useragent.MacOS},
+	{useragent.Firefox, useragent.Linux},
+	{useragent.Firefox, useragent.Android},
+	{useragent.Firefox, useragent.IOS},
+	{useragent.Safari, useragent.MacOS},
+	{useragent.Safari, useragent.IOS},
+	{useragent.Edge, useragent.Windows},
+	{useragent.Edge, useragent.MacOS},
+	{useragent.Edge, useragent.Android},
+	{useragent.Opera, useragent.Windows},
+	{useragent.Opera, useragent.Android},
+}
+
+// RandomUA returns browser user agent picked by the seed, the same seed
+// returns the same user agent. Browser, OS, versions and iPad are picked
+// from the combinations supported by useragent.Format, so it can be used
+// for property tests, like that every generated user agent is a browser:
+//
+//	for seed := int64(0); seed < 1000; seed++ {
+//		ua := uatest.RandomUA(seed)
+//		if ua.Bot || ua.IsUnknown() { ... }
+//	}
+func RandomUA(seed int64) useragent.UserAgent {
+	r := rand.New(rand.NewSource(seed))
+	c := combinations[r.Intn(len(combinations))]
+	spec := useragent.UserAgent{Name: c.name, OS: c.os}
+
+	switch c.name {
+	case useragent.Chrome:
+		spec.Version = version(r, 100, 131) + ".0.0.0"
+	case useragent.Firefox:
+		spec.Version = version(r, 100, 132) + ".0"
+	case useragent.Safari:
+		spec.Version = version(r, 14, 18) + ".0"
+	}
+	switch c.os {
+	case useragent.Android:
+		spec.OSVersion = version(r, 9, 15)
+	case useragent.IOS:
+		spec.OSVersion = version(r, 14, 18) + ".0"
+		spec.Tablet = r.Intn(4) == 0
+	}
+	return format(spec)
+}
+
+// version returns random major version in [min, max)
+func version(r *rand.Rand, min, max int) string {
+	return strconv.Itoa(min + r.Intn(max-min))
+}
+
+// format returns parsed user agent generated from the spec, fixtures
+// are supported by useragent.Format so the error is not expected
+func format(spec useragent.UserAgent) useragent.UserAgent {
+	s, err := useragent.Format(spec)
+	if err != nil {
+		panic("uatest: " + err.Error())
+	}
+	return useragent.Parse(s)
+}
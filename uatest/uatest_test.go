@@ -0,0 +1,58 @@
+package uatest_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/useragent"
+	"github.com/mileusna/useragent/uatest"
+)
+
+func TestFixtures(t *testing.T) {
+	tests := []struct {
+		fixture useragent.UserAgent
+		name    string
+		os      string
+		device  useragent.DeviceType
+	}{
+		{uatest.ChromeWindows(), useragent.Chrome, useragent.Windows, useragent.DeviceDesktop},
+		{uatest.ChromeMac(), useragent.Chrome, useragent.MacOS, useragent.DeviceDesktop},
+		{uatest.ChromeAndroid(), useragent.Chrome, useragent.Android, useragent.DevicePhone},
+		{uatest.FirefoxWindows(), useragent.Firefox, useragent.Windows, useragent.DeviceDesktop},
+		{uatest.FirefoxLinux(), useragent.Firefox, useragent.Linux, useragent.DeviceDesktop},
+		{uatest.EdgeWindows(), useragent.Edge, useragent.Windows, useragent.DeviceDesktop},
+		{uatest.SafariMac(), useragent.Safari, useragent.MacOS, useragent.DeviceDesktop},
+		{uatest.SafariIPhone(""), useragent.Safari, useragent.IOS, useragent.DevicePhone},
+		{uatest.SafariIPad(""), useragent.Safari, useragent.IOS, useragent.DeviceTablet},
+		{uatest.Googlebot(), useragent.Googlebot, "", useragent.DeviceBot},
+		{uatest.GooglebotSmartphone(), useragent.Googlebot, useragent.Android, useragent.DeviceBot},
+		{uatest.Bingbot(), useragent.Bingbot, "", useragent.DeviceBot},
+		{uatest.Curl(), "curl", "", useragent.DeviceUnknown},
+	}
+	for _, test := range tests {
+		f := test.fixture
+		if f.Name != test.name || f.OS != test.os || f.DeviceType() != test.device || f.Raw == "" {
+			t.Error("\n", f.Raw, "\nshould be", test.name, test.os, test.device, "not", f.Name, f.OS, f.DeviceType())
+		}
+	}
+
+	if ua := uatest.SafariIPhone("16.6"); ua.Version != "16.6" || ua.OSVersion != "16.6" {
+		t.Error("SafariIPhone version should be 16.6, not", ua.Version, ua.OSVersion)
+	}
+}
+
+func TestRandomUA(t *testing.T) {
+	if uatest.RandomUA(42).Raw != uatest.RandomUA(42).Raw {
+		t.Error("RandomUA should return the same user agent for the same seed")
+	}
+	seen := map[string]bool{}
+	for seed := int64(0); seed < 500; seed++ {
+		ua := uatest.RandomUA(seed)
+		if ua.Bot || ua.IsUnknown() || ua.Version == "" || ua.OS == "" {
+			t.Errorf("seed %d: %q should be browser, got %s %s on %s", seed, ua.Raw, ua.Name, ua.Version, ua.OS)
+		}
+		seen[ua.Name] = true
+	}
+	if len(seen) < 5 {
+		t.Error("RandomUA should return all the browsers, got", seen)
+	}
+}
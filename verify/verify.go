@@ -0,0 +1,137 @@
+// Package verify checks whether bots detected by useragent package really
+// come from their operators. Spoofed Googlebot and other well known bot user
+// agents are common, so the client IP is checked against the published IP
+// ranges or by reverse DNS lookup confirmed with forward DNS lookup.
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/mileusna/useragent"
+)
+
+// Domains of the bot operators, reverse DNS host name of the client IP
+// must be one of the domains or its subdomain. Googlebot doesn't crawl from
+// googleusercontent.com, any Google Cloud VM can have a PTR record there.
+var Domains = map[string][]string{
+	useragent.Googlebot:    {"googlebot.com", "google.com"},
+	useragent.GoogleAdsBot: {"google.com", "googlebot.com"},
+	useragent.Bingbot:      {"search.msn.com"},
+	useragent.Applebot:     {"applebot.apple.com"},
+	useragent.YandexBot:    {"yandex.ru", "yandex.net", "yandex.com"},
+}
+
+// Resolver used for DNS lookups, implemented by *net.Resolver
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Verifier verifies bots by IP ranges and DNS lookups
+type Verifier struct {
+	// Resolver for DNS lookups, net.DefaultResolver is used if nil
+	Resolver Resolver
+
+	// Ranges are published IP ranges per bot name (useragent.Googlebot etc.).
+	// If ranges for the bot are set, DNS lookup is done only if IP is not in range.
+	Ranges map[string][]*net.IPNet
+}
+
+var defaultVerifier = &Verifier{}
+
+// Verify checks whether the bot comes from IP address owned by its operator
+// using DNS lookups and sets ua.VerifiedBot
+func Verify(ctx context.Context, ua *useragent.UserAgent, ip net.IP) (bool, error) {
+	return defaultVerifier.Verify(ctx, ua, ip)
+}
+
+// Verify checks whether the bot comes from IP address owned by its operator
+// and sets ua.VerifiedBot. It returns false without error if user agent is not
+// one of the bots that can be verified.
+func (v *Verifier) Verify(ctx context.Context, ua *useragent.UserAgent, ip net.IP) (bool, error) {
+	ua.VerifiedBot = false
+	domains, ok := Domains[ua.Name]
+	if !ok || !ua.Bot || ip == nil {
+		return false, nil
+	}
+
+	for _, n := range v.Ranges[ua.Name] {
+		if n.Contains(ip) {
+			ua.VerifiedBot = true
+			return true, nil
+		}
+	}
+
+	var r Resolver = net.DefaultResolver
+	if v.Resolver != nil {
+		r = v.Resolver
+	}
+
+	hosts, err := r.LookupAddr(ctx, ip.String())
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, host := range hosts {
+		host = strings.TrimSuffix(host, ".")
+		if !inDomains(host, domains) {
+			continue
+		}
+		// forward lookup must return the same IP
+		addrs, err := r.LookupIPAddr(ctx, host)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(ip) {
+				ua.VerifiedBot = true
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// inDomains returns true if host is any of the domains or its subdomain
+func inDomains(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRanges parses IP ranges in JSON format published by Google and Bing
+// (googlebot.json, bingbot.json), {"prefixes": [{"ipv4Prefix": "..."}, {"ipv6Prefix": "..."}]}
+func ParseRanges(r io.Reader) ([]*net.IPNet, error) {
+	var data struct {
+		Prefixes []struct {
+			IPv4Prefix string `json:"ipv4Prefix"`
+			IPv6Prefix string `json:"ipv6Prefix"`
+		} `json:"prefixes"`
+	}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	ranges := make([]*net.IPNet, 0, len(data.Prefixes))
+	for _, p := range data.Prefixes {
+		prefix := p.IPv4Prefix
+		if prefix == "" {
+			prefix = p.IPv6Prefix
+		}
+		_, n, err := net.ParseCIDR(prefix)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, n)
+	}
+	return ranges, nil
+}
@@ -0,0 +1,78 @@
+package verify_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/useragent"
+	"github.com/mileusna/useragent/verify"
+)
+
+type fakeResolver struct {
+	ptr map[string][]string
+	ip  map[string][]net.IPAddr
+}
+
+func (r fakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	if hosts, ok := r.ptr[addr]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if addrs, ok := r.ip[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestVerify(t *testing.T) {
+	v := &verify.Verifier{
+		Resolver: fakeResolver{
+			ptr: map[string][]string{
+				"66.249.66.1": {"crawl-66-249-66-1.googlebot.com."},
+				"10.0.0.1":    {"crawl-66-249-66-1.googlebot.com."}, // spoofed PTR
+				"10.0.0.2":    {"evil.example.com."},
+				"34.1.2.3":    {"3.2.1.34.bc.googleusercontent.com."}, // Google Cloud VM
+			},
+			ip: map[string][]net.IPAddr{
+				"crawl-66-249-66-1.googlebot.com":   {{IP: net.ParseIP("66.249.66.1")}},
+				"3.2.1.34.bc.googleusercontent.com": {{IP: net.ParseIP("34.1.2.3")}},
+			},
+		},
+	}
+	ranges, err := verify.ParseRanges(strings.NewReader(`{"prefixes": [{"ipv4Prefix": "157.55.39.0/24"}, {"ipv6Prefix": "2620:0:1c00::/40"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Ranges = map[string][]*net.IPNet{useragent.Bingbot: ranges}
+
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	bingbot := "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)"
+	tests := []struct {
+		ua, ip   string
+		verified bool
+	}{
+		{googlebot, "66.249.66.1", true},
+		{googlebot, "10.0.0.1", false},
+		{googlebot, "10.0.0.2", false},
+		{googlebot, "10.0.0.3", false},
+		{googlebot, "34.1.2.3", false},
+		{bingbot, "157.55.39.10", true},
+		{bingbot, "10.0.0.3", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "66.249.66.1", false},
+	}
+	for _, test := range tests {
+		ua := useragent.Parse(test.ua)
+		ok, err := v.Verify(context.Background(), &ua, net.ParseIP(test.ip))
+		if err != nil {
+			t.Error(test.ip, err)
+		}
+		if ok != test.verified || ua.VerifiedBot != test.verified {
+			t.Error("\n", test.ua, test.ip, "verified should be", test.verified)
+		}
+	}
+}
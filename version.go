@@ -2,38 +2,187 @@ package useragent
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
+// VersionNo is version string parsed into numbers, like 120.0.6099.71 with
+// the fourth number in Build. Text following the numbers, like b4948 in
+// 8.1.1b4948 or ~bl in 7~bl, is kept in Suffix.
 type VersionNo struct {
-	Major int
-	Minor int
-	Patch int
+	Major  int
+	Minor  int
+	Patch  int
+	Build  int    // fourth number, like Chrome build 71 in 120.0.6099.71
+	Suffix string // pre-release or build text with its separator, like "-1" or "b4948"
 }
 
-// parseVersion parse version string into Major.Minor.Patch struct
+// ParseVersion parses version string, like "120.0.6099.71", "8.1.1b4948"
+// or "7~bl", into VersionNo. Up to four dot separated numbers are parsed,
+// more numbers are skipped, and the rest of the version is the Suffix.
+// Numbers larger than int32 are not version numbers, they are the Suffix.
+// Version not starting with a digit returns zero VersionNo.
+func ParseVersion(version string) VersionNo {
+	return parseVersion(version)
+}
+
+// parseVersion parse version string into Major.Minor.Patch.Build struct
 func parseVersion(ver string) (verno VersionNo) {
-	var err error
-	parts := strings.Split(ver, ".")
-	if len(parts) > 0 {
-		if verno.Major, err = strconv.Atoi(parts[0]); err != nil {
-			return verno
-		}
-		if len(parts) > 1 {
-			if verno.Minor, err = strconv.Atoi(parts[1]); err != nil {
-				return verno
+	parts := [4]*int{&verno.Major, &verno.Minor, &verno.Patch, &verno.Build}
+	i := 0
+	for n := 0; ; n++ {
+		start := i
+		num := 0
+		for i < len(ver) && '0' <= ver[i] && ver[i] <= '9' {
+			num = num*10 + int(ver[i]-'0')
+			i++
+			if num > math.MaxInt32 {
+				i = start // not a version number, like a timestamp
+				break
 			}
-			if len(parts) > 2 {
-				if verno.Patch, err = strconv.Atoi(parts[2]); err != nil {
-					return verno
-				}
+		}
+		if i == start {
+			if n == 0 {
+				return VersionNo{}
 			}
+			i-- // dot not followed by number is part of the suffix
+			break
+		}
+		if n < len(parts) {
+			*parts[n] = num
+		}
+		if i == len(ver) || ver[i] != '.' {
+			break
 		}
+		i++
 	}
+	verno.Suffix = ver[i:]
 	return verno
 }
 
+// String returns version in format <Major>.<Minor>.<Patch>[.<Build>]<Suffix>,
+// like "120.0.6099.71" or "8.1.1b4948", or empty string for zero VersionNo.
+// Build is omitted when zero. ParseVersion parses the returned string into
+// the same VersionNo.
+func (v VersionNo) String() string {
+	if v == (VersionNo{}) {
+		return ""
+	}
+	s := strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+	if v.Build != 0 {
+		s += "." + strconv.Itoa(v.Build)
+	}
+	return s + v.Suffix
+}
+
+// Compare returns -1 if v is lower than w, 0 if they are equal and +1 if v
+// is higher. Numbers are compared in order, Major, Minor, Patch and Build.
+// Suffix is compared like pre-release in semantic versioning, so a version
+// with suffix is lower than the same version without it, 1.0.0-beta is lower
+// than 1.0.0, and build metadata suffix, like +20130313, is ignored.
+func (v VersionNo) Compare(w VersionNo) int {
+	for _, n := range [...][2]int{{v.Major, w.Major}, {v.Minor, w.Minor}, {v.Patch, w.Patch}, {v.Build, w.Build}} {
+		if c := compareInt(n[0], n[1]); c != 0 {
+			return c
+		}
+	}
+	return comparePreRelease(v.Suffix, w.Suffix)
+}
+
+// Less returns true if v is lower than w, like in sort.Slice
+func (v VersionNo) Less(w VersionNo) bool {
+	return v.Compare(w) < 0
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePreRelease compares suffixes by semantic versioning pre-release
+// precedence. Suffix without build metadata and the leading separator is
+// split into dot separated identifiers, numeric identifiers are compared
+// numerically and are lower than alphanumeric ones, which are compared
+// in ASCII order. Empty suffix is the release, higher than any pre-release.
+func comparePreRelease(a, b string) int {
+	a, b = preRelease(a), preRelease(b)
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	for a != "" && b != "" {
+		var x, y string
+		x, a = nextIdentifier(a)
+		y, b = nextIdentifier(b)
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		var c int
+		switch {
+		case xNum && yNum:
+			// compare without leading zeros, longer number is higher
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if c = compareInt(len(x), len(y)); c == 0 {
+				c = strings.Compare(x, y)
+			}
+		case xNum:
+			c = -1
+		case yNum:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	// more identifiers is higher
+	return compareInt(len(a), len(b))
+}
+
+// preRelease returns suffix without build metadata and the leading separator
+func preRelease(suffix string) string {
+	if i := strings.IndexByte(suffix, '+'); i >= 0 {
+		suffix = suffix[:i]
+	}
+	return strings.TrimLeft(suffix, "-~._")
+}
+
+// nextIdentifier returns the first dot separated identifier and the rest
+func nextIdentifier(s string) (identifier, rest string) {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+func isNumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// MajorVersion returns major version of the browser, or 0 if unknown
+func (ua UserAgent) MajorVersion() int {
+	return ua.VersionNo.Major
+}
+
+// OSMajorVersion returns major version of the OS, or 0 if unknown
+func (ua UserAgent) OSMajorVersion() int {
+	return ua.OSVersionNo.Major
+}
+
 // VersionNoShort return version string in format <Major>.<Minor>
 func (ua UserAgent) VersionNoShort() string {
 	if ua.VersionNo.Major == 0 && ua.VersionNo.Minor == 0 && ua.VersionNo.Patch == 0 {
@@ -65,3 +214,35 @@ func (ua UserAgent) OSVersionNoFull() string {
 	}
 	return fmt.Sprintf("%d.%d.%d", ua.OSVersionNo.Major, ua.OSVersionNo.Minor, ua.OSVersionNo.Patch)
 }
+
+// OSVersionName returns consumer release name for Windows NT versions, like
+// "Windows 7" or "Windows 10/11", or empty string for other operating systems.
+// Windows 11 still reports NT 10.0, so it can't be distinguished from Windows 10
+// unless Sec-CH-UA-Platform-Version hint is merged, which sets OSVersion to
+// "10" or "11".
+func (ua UserAgent) OSVersionName() string {
+	if ua.OS != Windows {
+		return ""
+	}
+	switch ua.OSVersion {
+	case "5.0":
+		return "Windows 2000"
+	case "5.1", "5.2":
+		return "Windows XP"
+	case "6.0":
+		return "Windows Vista"
+	case "6.1":
+		return "Windows 7"
+	case "6.2":
+		return "Windows 8"
+	case "6.3":
+		return "Windows 8.1"
+	case "10.0":
+		return "Windows 10/11"
+	case "10":
+		return "Windows 10"
+	case "11":
+		return "Windows 11"
+	}
+	return ""
+}
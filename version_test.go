@@ -0,0 +1,167 @@
+package useragent_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	ua "github.com/mileusna/useragent"
+)
+
+func TestVersionHelpers(t *testing.T) {
+	tests := []struct {
+		version string
+		major   int
+		short   string
+		full    string
+	}{
+		{"", 0, "", ""},
+		{"120", 120, "120.0", "120.0.0"},
+		{"120.0.6099.109", 120, "120.0", "120.0.6099"},
+		{"13.1.2", 13, "13.1", "13.1.2"},
+		{"5.0.1-1", 5, "5.0", "5.0.1"},
+		{"abc", 0, "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse("TestBrowser/" + test.version)
+		if agent.Version != test.version {
+			t.Fatalf("Version should be %q not %q", test.version, agent.Version)
+		}
+		if agent.MajorVersion() != test.major || agent.VersionNoShort() != test.short || agent.VersionNoFull() != test.full {
+			t.Errorf("%q: got %d %q %q, expected %d %q %q", test.version,
+				agent.MajorVersion(), agent.VersionNoShort(), agent.VersionNoFull(), test.major, test.short, test.full)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		verno   ua.VersionNo
+		str     string
+	}{
+		{"", ua.VersionNo{}, ""},
+		{"abc", ua.VersionNo{}, ""},
+		{"120", ua.VersionNo{Major: 120}, "120.0.0"},
+		{"120.0.6099.109", ua.VersionNo{Major: 120, Patch: 6099, Build: 109}, "120.0.6099.109"},
+		{"1.2.3.4.5", ua.VersionNo{Major: 1, Minor: 2, Patch: 3, Build: 4}, "1.2.3.4"},
+		{"8.1.1b4948", ua.VersionNo{Major: 8, Minor: 1, Patch: 1, Suffix: "b4948"}, "8.1.1b4948"},
+		{"7~bl", ua.VersionNo{Major: 7, Suffix: "~bl"}, "7.0.0~bl"},
+		{"5.0.1-1", ua.VersionNo{Major: 5, Patch: 1, Suffix: "-1"}, "5.0.1-1"},
+		{"2.0rc1", ua.VersionNo{Major: 2, Suffix: "rc1"}, "2.0.0rc1"},
+		{"1.2.3.4-beta", ua.VersionNo{Major: 1, Minor: 2, Patch: 3, Build: 4, Suffix: "-beta"}, "1.2.3.4-beta"},
+		{"1.2.3.0-beta", ua.VersionNo{Major: 1, Minor: 2, Patch: 3, Suffix: "-beta"}, "1.2.3-beta"},
+		{"1.x", ua.VersionNo{Major: 1, Suffix: ".x"}, "1.0.0.x"},
+		{"3.", ua.VersionNo{Major: 3, Suffix: "."}, "3.0.0."},
+		{"1.20231231235959", ua.VersionNo{Major: 1, Suffix: ".20231231235959"}, "1.0.0.20231231235959"},
+		{"20231231235959", ua.VersionNo{}, ""},
+	}
+	for _, test := range tests {
+		verno := ua.ParseVersion(test.version)
+		if verno != test.verno || verno.String() != test.str {
+			t.Errorf("%q: got %#v %q, expected %#v %q", test.version, verno, verno.String(), test.verno, test.str)
+		}
+		if again := ua.ParseVersion(verno.String()); again != verno {
+			t.Errorf("%q: String %q parsed as %#v", test.version, verno.String(), again)
+		}
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	// ordered from the lowest
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2",
+		"2.8.9dev.16",
+		"2.8.9",
+		"120.0.6099.9",
+		"120.0.6099.71",
+		"120.0.6099.109",
+		"120.0.6100",
+	}
+	for i := range versions {
+		for j := range versions {
+			v, w := ua.ParseVersion(versions[i]), ua.ParseVersion(versions[j])
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if c := v.Compare(w); c != want || v.Less(w) != (want < 0) {
+				t.Errorf("%s compared to %s should be %d not %d", versions[i], versions[j], want, c)
+			}
+		}
+	}
+
+	for _, test := range []struct{ v, w string }{
+		{"1.0.0+20130313", "1.0.0"},
+		{"1.0.0-beta+exp.sha.5114f85", "1.0.0-beta"},
+		{"120.0.6099.0", "120.0.6099"},
+		{"1.0.0-beta.007", "1.0.0-beta.7"},
+		{"", ""},
+	} {
+		if c := ua.ParseVersion(test.v).Compare(ua.ParseVersion(test.w)); c != 0 {
+			t.Errorf("%s compared to %s should be 0 not %d", test.v, test.w, c)
+		}
+	}
+}
+
+func TestVersionCorpus(t *testing.T) {
+	for _, test := range loadCorpus(t) {
+		agent := ua.Parse(test.UserAgent)
+		if major, minor, ok := majorMinor(agent.Version); ok {
+			if agent.MajorVersion() != major || agent.VersionNoShort() != fmt.Sprintf("%d.%d", major, minor) {
+				t.Errorf("\n%s\nversion %q: MajorVersion %d, VersionNoShort %q", test.UserAgent, agent.Version, agent.MajorVersion(), agent.VersionNoShort())
+			}
+		}
+		if major, minor, ok := majorMinor(agent.OSVersion); ok {
+			if agent.OSMajorVersion() != major || agent.OSVersionNoShort() != fmt.Sprintf("%d.%d", major, minor) {
+				t.Errorf("\n%s\nOS version %q: OSMajorVersion %d, OSVersionNoShort %q", test.UserAgent, agent.OSVersion, agent.OSMajorVersion(), agent.OSVersionNoShort())
+			}
+		}
+		if agent.OS == ua.Windows && agent.OSVersion != "" && agent.OSVersionName() == "" {
+			t.Errorf("\n%s\nno OSVersionName for Windows NT %s", test.UserAgent, agent.OSVersion)
+		}
+	}
+}
+
+// majorMinor returns the first two numeric components of the version
+func majorMinor(version string) (major, minor int, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	var err error
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, false
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+func TestFindVersion(t *testing.T) {
+	// findVersion replaced regexp, results must stay the same
+	rx := regexp.MustCompile(`[_\d\.]+`)
+	for _, s := range []string{
+		"", "Intel Mac OS X 10_15_7", "CPU iPhone OS 17_2_1 like Mac OS X", "CPU OS 16_6 like Mac OS X",
+		"Intel Mac OS X 10.6", "Mac OS X", "PPC Mac OS X 10_4_11", "iOS _", "x.y", "OS 12_0 and 13_1", "١٢٣ 4",
+	} {
+		want := strings.Replace(rx.FindString(s), "_", ".", -1)
+		if got := ua.FindVersion(s); got != want {
+			t.Errorf("findVersion(%q) = %q, should be %q", s, got, want)
+		}
+	}
+}
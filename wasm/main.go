@@ -0,0 +1,92 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command wasm exports Parse to JavaScript, so frontend and edge code can
+// use the same detection as Go backend. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o useragent.wasm ./wasm
+//
+// and load it with wasm_exec.js from Go distribution. It sets global
+// useragent object with parse(string) and isBot(string) functions.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/mileusna/useragent"
+)
+
+func main() {
+	js.Global().Set("useragent", js.ValueOf(map[string]interface{}{
+		"parse": js.FuncOf(parse),
+		"isBot": js.FuncOf(isBot),
+	}))
+	// keep exported functions available
+	select {}
+}
+
+// parse returns parse result of the first argument as plain object
+func parse(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return js.Null()
+	}
+	ua := useragent.Parse(args[0].String())
+	anomalies := make([]interface{}, len(ua.Anomalies))
+	for i, a := range ua.Anomalies {
+		anomalies[i] = a
+	}
+	urls := make([]interface{}, len(ua.URLs))
+	for i, u := range ua.URLs {
+		urls[i] = u
+	}
+	return map[string]interface{}{
+		"raw":             ua.Raw,
+		"name":            ua.Name,
+		"version":         ua.Version,
+		"os":              ua.OS,
+		"osVersion":       ua.OSVersion,
+		"osDistro":        ua.OSDistro,
+		"osDistroVersion": ua.OSDistroVersion,
+		"device":          ua.Device,
+		"deviceVendor":    ua.DeviceVendor,
+		"deviceModel":     ua.DeviceModel,
+		"deviceType":      ua.DeviceType().String(),
+		"mobile":          ua.Mobile,
+		"tablet":          ua.Tablet,
+		"desktop":         ua.Desktop,
+		"bot":             ua.Bot,
+		"botReason":       ua.BotReason,
+		"botContact":      ua.BotContact,
+		"tool":            ua.Tool,
+		"textBrowser":     ua.TextBrowser,
+		"prefetch":        ua.Prefetch,
+		"automationTool":  ua.AutomationTool,
+		"category":        ua.Category,
+		"truncated":       ua.Truncated,
+		"url":             ua.URL,
+		"urls":            urls,
+		"arch":            ua.Arch,
+		"locale":          ua.Locale,
+		"region":          ua.Region,
+		"androidBuild":    ua.AndroidBuild,
+		"webView":         ua.WebView,
+		"maybeIPad":       ua.MaybeIPad,
+		"desktopMode":     ua.DesktopMode,
+		"appName":         ua.AppName,
+		"appVersion":      ua.AppVersion,
+		"browserName":     ua.BrowserName,
+		"browserVersion":  ua.BrowserVersion,
+		"engine":          ua.Engine,
+		"engineVersion":   ua.EngineVersion,
+		"electron":        ua.Electron,
+		"anomalies":       anomalies,
+	}
+}
+
+// isBot reports whether the first argument is a bot user agent
+func isBot(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return false
+	}
+	return useragent.IsBot(args[0].String())
+}